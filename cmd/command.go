@@ -46,34 +46,55 @@ func mainCommand() (*cobra.Command, error) {
 	if err := viperConfiguration.Unmarshal(&config, func(decoderConfig *mapstructure.DecoderConfig) {
 		decoderConfig.DecodeHook = mapstructure.ComposeDecodeHookFunc(
 			configuration.DecodeOrigin,
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.TextUnmarshallerHookFunc(),
 		)
 	}); err != nil {
 		return nil, err
 	}
 
 	output := &cobra.Command{
-		Use:   "cnc-router <filename.dxf>",
-		Short: "Generate gcode from dxf",
+		Use:   "cnc-router <filename.dxf|svg>",
+		Short: "Generate gcode from dxf or svg",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			files = make([]string, len(args))
 
 			copy(files, args)
 
-			return nil
+			// The arguments are valid: the errors from now on are not about the usage.
+			cmd.SilenceUsage = true
+
+			return config.SelectMachine(cmd.Flags().Changed("post") || viperConfiguration.IsSet("post"))
 		},
 	}
 
 	output.PersistentFlags().Float64VarP(&config.Feed, "feed", "f", config.Feed, "speed in millimeters per minute")
 
 	output.PersistentFlags().Float64VarP(&config.SecurityZ, "security-z", "z", config.SecurityZ, "Z security in millimeters")
+	output.PersistentFlags().Float64VarP(&config.PlungeFeed, "plunge-feed", "", config.PlungeFeed, "speed in millimeters per minute when going down (feed when 0)")
+	output.PersistentFlags().Float64VarP(&config.RetractFeed, "retract-feed", "", config.RetractFeed, "speed in millimeters per minute when going up (rapid when 0)")
+	output.PersistentFlags().Float64VarP(&config.Clearance, "clearance", "", config.Clearance, "height in millimeters where the tool stops going down at rapid speed (security Z when 0)")
+	output.PersistentFlags().Float64VarP(&config.RapidFeed, "rapid-feed", "", config.RapidFeed, "rapid speed in millimeters per minute, for the time estimate")
 	output.PersistentFlags().StringArrayVarP(&config.Layers, "layer", "l", config.Layers, "layer to filter")
+	output.PersistentFlags().VarP(&config.Post, "post", "", "post-processor (none, grbl, linuxcnc, mach3, marlin, fanuc)")
+	output.PersistentFlags().Float64VarP(&config.SpindleSpeed, "spindle-speed", "", config.SpindleSpeed, "spindle speed in rpm, or laser power (0 to leave the spindle alone)")
+	output.PersistentFlags().VarP(&config.SpindleDirection, "spindle-direction", "", "spindle direction (cw, ccw)")
+	output.PersistentFlags().DurationVarP(&config.SpindleDwell, "spindle-dwell", "", config.SpindleDwell, "pause after starting the spindle")
+	output.PersistentFlags().VarP(&config.Coolant, "coolant", "", "coolant (off, mist, flood)")
+	output.PersistentFlags().BoolVarP(&config.Laser, "laser", "", config.Laser, "laser mode: the power is set on each cutting move")
+	output.PersistentFlags().StringVarP(&config.Preview, "preview", "", config.Preview, "write a preview of the toolpath to <preview>.svg and <preview>.png")
+	output.PersistentFlags().StringVarP(&config.Machine, "machine", "", config.Machine, "machine profile (from the machines of the config file) the program is checked against")
+	output.PersistentFlags().VarP(&config.Report, "report", "", "machining time report on stderr (text, json, none)")
 
 	output.AddCommand(
 		drillCommand(&files, &config),
 		engraveCommand(&files, &config),
+		engraveTextCommand(&config),
 		infoCommand(&files, &config),
 		configFileCommand(&config),
 		surfaceCommand(&config),
+		pocketCommand(&files, &config),
+		simulateCommand(&files, &config),
 	)
 
 	return output, nil
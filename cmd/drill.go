@@ -7,15 +7,21 @@ import (
 
 	"github.com/landru29/cnc-drilling/internal/configuration"
 	"github.com/landru29/cnc-drilling/internal/driller"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/simulator"
 	"github.com/spf13/cobra"
 )
 
 func drillCommand(files *[]string, config *configuration.Config) *cobra.Command {
 	output := &cobra.Command{
-		Use:   "drill <filename.dxf>",
-		Short: "Generate gcode to drill from dxf",
+		Use:   "drill <filename.dxf|svg|drl>",
+		Short: "Generate gcode to drill from dxf or svg",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			viewer := newPreviewer(*config)
+			guard := simulator.NewGuard(viewer.writer(cmd.OutOrStdout()), *config)
+			out := gcode.NewWriter(guard, config.Post, config.Spindle())
+
 			for _, file := range *files {
 				fileDesc, err := os.Open(file)
 				if err != nil {
@@ -26,30 +32,48 @@ func drillCommand(files *[]string, config *configuration.Config) *cobra.Command
 					_ = closer.Close()
 				}(fileDesc)
 
-				if err := header(cmd.OutOrStdout(), file); err != nil {
+				if err := header(out, file); err != nil {
 					return err
 				}
 
 				if err := driller.Process(
 					fileDesc,
-					cmd.OutOrStdout(),
+					out,
 					*config,
 				); err != nil {
 					return err
 				}
 
-				if err := footer(cmd.OutOrStdout(), file); err != nil {
+				if err := footer(out, file); err != nil {
 					return err
 				}
+
+				if err := viewer.addSource(file, *config); err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+			}
+
+			if err := out.Close(); err != nil {
+				return err
+			}
+
+			if err := guard.Close(); err != nil {
+				return err
 			}
 
-			return nil
+			return viewer.write(cmd.ErrOrStderr(), *config)
 		},
 	}
 
 	output.Flags().Float64VarP(&config.Deepness, "deep", "d", config.Deepness, "drilling deep in millimeters")
 	output.Flags().Float64VarP(&config.DeepPerTry, "deep-per-try", "", config.DeepPerTry, "max deep in millimeters during one try")
+	output.Flags().VarP(&config.Order, "order", "", "order of the tries (level: all the paths at one deep, depth: each path to the full deep)")
 	output.Flags().VarP(&config.Origin, "origin", "o", "shift origin")
+	output.Flags().Float64VarP(&config.ToolDiameter, "tool-diameter", "t", config.ToolDiameter, "tool diameter in millimeters")
+	output.Flags().BoolVarP(&config.Circles, "circles", "", config.Circles, "drill circles as holes, interpolating the ones larger than the tool")
+	output.Flags().BoolVarP(&config.Optimize, "optimize", "", config.Optimize, "optimize the drilling order to shorten the rapid moves")
+	output.Flags().DurationVarP(&config.OptimizeTime, "optimize-time", "", config.OptimizeTime, "time budget to optimize the drilling order")
+	output.Flags().VarP(&config.Cycle, "cycle", "", "drilling cycle (none, g81, g83, g73, expanded)")
 
 	return output
 }
@@ -7,15 +7,21 @@ import (
 
 	"github.com/landru29/cnc-drilling/internal/configuration"
 	"github.com/landru29/cnc-drilling/internal/engraver"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/simulator"
 	"github.com/spf13/cobra"
 )
 
 func engraveCommand(files *[]string, config *configuration.Config) *cobra.Command {
 	output := &cobra.Command{
-		Use:   "engrave <filename.dxf>",
-		Short: "Generate gcode to engrave from dxf",
+		Use:   "engrave <filename.dxf|svg>",
+		Short: "Generate gcode to engrave from dxf or svg",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			viewer := newPreviewer(*config)
+			guard := simulator.NewGuard(viewer.writer(cmd.OutOrStdout()), *config)
+			out := gcode.NewWriter(guard, config.Post, config.Spindle())
+
 			for _, file := range *files {
 				fileDesc, err := os.Open(file)
 				if err != nil {
@@ -26,30 +32,58 @@ func engraveCommand(files *[]string, config *configuration.Config) *cobra.Comman
 					_ = closer.Close()
 				}(fileDesc)
 
-				if err := header(cmd.OutOrStdout(), file); err != nil {
+				if err := header(out, file); err != nil {
 					return err
 				}
 
 				if err := engraver.Process(
-					fileDesc, cmd.OutOrStdout(),
+					fileDesc, out,
 					*config,
 				); err != nil {
 					return err
 				}
 
-				if err := footer(cmd.OutOrStdout(), file); err != nil {
+				if err := footer(out, file); err != nil {
 					return err
 				}
+
+				if err := viewer.addSource(file, *config); err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+			}
+
+			if err := out.Close(); err != nil {
+				return err
+			}
+
+			if err := guard.Close(); err != nil {
+				return err
 			}
 
-			return nil
+			return viewer.write(cmd.ErrOrStderr(), *config)
 		},
 	}
 
 	output.Flags().Float64VarP(&config.Deepness, "deep", "d", config.Deepness, "engrave deep in millimeters")
 	output.Flags().Float64VarP(&config.DeepStart, "deep-start", "", config.DeepStart, "initial deep in millimeters")
 	output.Flags().Float64VarP(&config.DeepPerTry, "deep-per-try", "", config.DeepPerTry, "max deep in millimeters during one try")
+	output.Flags().VarP(&config.Order, "order", "", "order of the tries (level: all the paths at one deep, depth: each path to the full deep)")
+	output.Flags().VarP(&config.Entry, "entry", "", "way of going down into the material (plunge, ramp, helix)")
+	output.Flags().Float64VarP(&config.RampAngle, "ramp-angle", "", config.RampAngle, "max angle in degrees of the tool going down with a ramp or a helix")
+	output.Flags().VarP(&config.Milling, "milling", "", "cutting direction along closed paths (any, climb, conventional)")
+	output.Flags().BoolVarP(&config.Spiral, "spiral", "", config.Spiral, "cut closed paths going down continuously, with a last turn at the deep")
+	output.Flags().BoolVarP(&config.InnerFirst, "inner-first", "", config.InnerFirst, "cut the closed paths nested in other closed paths before them")
 	output.Flags().VarP(&config.Origin, "origin", "o", "shift origin")
+	output.Flags().Float64VarP(&config.ToolDiameter, "tool-diameter", "t", config.ToolDiameter, "tool diameter in millimeters")
+	output.Flags().VarP(&config.Side, "side", "", "tool side on closed paths (inside, outside, on)")
+	output.Flags().VarP(&config.Lead, "lead", "", "lead-in and lead-out of compensated closed paths (none, arc, line)")
+	output.Flags().Float64VarP(&config.LeadLength, "lead-length", "", config.LeadLength, "radius of the lead arcs or length of the lead lines in millimeters")
+	output.Flags().Float64VarP(&config.Overlap, "overlap", "", config.Overlap, "distance cut past the start of compensated closed paths in millimeters")
+	output.Flags().IntVarP(&config.TabCount, "tabs", "", config.TabCount, "number of tabs on each closed path")
+	output.Flags().Float64VarP(&config.TabSpacing, "tab-spacing", "", config.TabSpacing, "distance between tabs in millimeters (overrides --tabs)")
+	output.Flags().Float64VarP(&config.TabWidth, "tab-width", "", config.TabWidth, "width of material left by each tab in millimeters")
+	output.Flags().Float64VarP(&config.TabHeight, "tab-height", "", config.TabHeight, "height of the tabs in millimeters (0 disables tabs)")
+	output.Flags().StringVarP(&config.TabLayer, "tab-layer", "", config.TabLayer, "layer with points placing tabs")
 
 	return output
 }
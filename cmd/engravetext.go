@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/engraver"
+	"github.com/landru29/cnc-drilling/internal/font"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+func engraveTextCommand(config *configuration.Config) *cobra.Command {
+	style := font.Style{
+		Height: 5,
+	}
+
+	output := &cobra.Command{
+		Use:   "engrave-text <text>",
+		Short: "Generate gcode to engrave a text with a single-stroke font",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			viewer := newPreviewer(*config)
+			guard := simulator.NewGuard(viewer.writer(cmd.OutOrStdout()), *config)
+			out := gcode.NewWriter(guard, config.Post, config.Spindle())
+
+			if err := engraver.ProcessText(strings.Join(args, " "), style, out, *config); err != nil {
+				return err
+			}
+
+			if err := out.Close(); err != nil {
+				return err
+			}
+
+			if err := guard.Close(); err != nil {
+				return err
+			}
+
+			return viewer.write(cmd.ErrOrStderr(), *config)
+		},
+	}
+
+	output.Flags().Float64VarP(&style.Height, "height", "", style.Height, "height of the capital letters in millimeters")
+	output.Flags().Float64VarP(&style.Spacing, "spacing", "", style.Spacing, "extra space between letters in millimeters")
+	output.Flags().Float64VarP(&style.Rotation, "rotation", "", style.Rotation, "counterclockwise rotation in degrees")
+	output.Flags().VarP(&style.Align, "align", "", "horizontal alignment on the position (left, center, right)")
+	output.Flags().VarP(&style.Vertical, "vertical-align", "", "vertical alignment on the position (baseline, bottom, middle, top)")
+	output.Flags().VarP(&style.Position, "position", "p", "text position")
+	output.Flags().Float64VarP(&config.Deepness, "deep", "d", config.Deepness, "engrave deep in millimeters")
+	output.Flags().Float64VarP(&config.DeepStart, "deep-start", "", config.DeepStart, "initial deep in millimeters")
+	output.Flags().Float64VarP(&config.DeepPerTry, "deep-per-try", "", config.DeepPerTry, "max deep in millimeters during one try")
+	output.Flags().VarP(&config.Order, "order", "", "order of the tries (level: all the paths at one deep, depth: each path to the full deep)")
+	output.Flags().VarP(&config.Entry, "entry", "", "way of going down into the material (plunge, ramp, helix)")
+	output.Flags().Float64VarP(&config.RampAngle, "ramp-angle", "", config.RampAngle, "max angle in degrees of the tool going down with a ramp or a helix")
+	output.Flags().VarP(&config.Milling, "milling", "", "cutting direction along closed paths (any, climb, conventional)")
+	output.Flags().BoolVarP(&config.Spiral, "spiral", "", config.Spiral, "cut closed paths going down continuously, with a last turn at the deep")
+	output.Flags().VarP(&config.Origin, "origin", "o", "shift origin")
+
+	return output
+}
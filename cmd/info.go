@@ -12,9 +12,9 @@ import (
 )
 
 func infoCommand(files *[]string, config *configuration.Config) *cobra.Command {
-	return &cobra.Command{
-		Use:   "info <filename.dxf>",
-		Short: "Display informations about DXF",
+	output := &cobra.Command{
+		Use:   "info <filename.dxf|svg>",
+		Short: "Display informations about DXF or SVG",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			for _, file := range *files {
@@ -35,6 +35,10 @@ func infoCommand(files *[]string, config *configuration.Config) *cobra.Command {
 			return nil
 		},
 	}
+
+	output.Flags().DurationVarP(&config.OptimizeTime, "optimize-time", "", config.OptimizeTime, "time budget to optimize the drilling order")
+
+	return output
 }
 
 func configFileCommand(config *configuration.Config) *cobra.Command {
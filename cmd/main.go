@@ -26,6 +26,6 @@ func main() {
 	}
 
 	if err := mainCmd.ExecuteContext(ctx); err != nil {
-		panic(err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/pocketer"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+func pocketCommand(files *[]string, config *configuration.Config) *cobra.Command {
+	var (
+		step   float64
+		method pocketer.Method
+	)
+
+	output := &cobra.Command{
+		Use:   "pocket <filename.dxf|svg>",
+		Short: "Generate gcode to clear pockets from dxf or svg",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			viewer := newPreviewer(*config)
+			guard := simulator.NewGuard(viewer.writer(cmd.OutOrStdout()), *config)
+			out := gcode.NewWriter(guard, config.Post, config.Spindle())
+
+			for _, file := range *files {
+				fileDesc, err := os.Open(file)
+				if err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+
+				defer func(closer io.Closer) {
+					_ = closer.Close()
+				}(fileDesc)
+
+				if err := header(out, file); err != nil {
+					return err
+				}
+
+				if err := pocketer.Process(
+					fileDesc,
+					out,
+					*config,
+					step,
+					method,
+				); err != nil {
+					return err
+				}
+
+				if err := footer(out, file); err != nil {
+					return err
+				}
+
+				if err := viewer.addSource(file, *config); err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+			}
+
+			if err := out.Close(); err != nil {
+				return err
+			}
+
+			if err := guard.Close(); err != nil {
+				return err
+			}
+
+			return viewer.write(cmd.ErrOrStderr(), *config)
+		},
+	}
+
+	output.Flags().VarP(&method, "method", "m", "pocket clearing method (contour, zigzag)")
+	output.Flags().Float64VarP(&step, "step", "s", 0, "stepover between each pass (XY plane) in mm. half the tool diameter when not set")
+	output.Flags().Float64VarP(&config.Deepness, "deep", "d", config.Deepness, "pocket deep in millimeters")
+	output.Flags().Float64VarP(&config.DeepStart, "deep-start", "", config.DeepStart, "initial deep in millimeters")
+	output.Flags().Float64VarP(&config.DeepPerTry, "deep-per-try", "", config.DeepPerTry, "max deep in millimeters during one try")
+	output.Flags().VarP(&config.Entry, "entry", "", "way of going down into the material (plunge, ramp, helix)")
+	output.Flags().Float64VarP(&config.RampAngle, "ramp-angle", "", config.RampAngle, "max angle in degrees of the tool going down with a ramp or a helix")
+	output.Flags().VarP(&config.Origin, "origin", "o", "shift origin")
+	output.Flags().Float64VarP(&config.ToolDiameter, "tool-diameter", "t", config.ToolDiameter, "tool diameter in millimeters")
+
+	return output
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/preview"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+)
+
+// previewSize is the size of the PNG preview in pixels.
+const previewSize = 1200
+
+// previewer keeps the gcode of a job to render its preview and to report its machining time, when
+// asked.
+type previewer struct {
+	name    string
+	report  configuration.Report
+	program bytes.Buffer
+	scene   preview.Scene
+}
+
+func newPreviewer(config configuration.Config) *previewer {
+	name := config.Preview
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".svg", ".png":
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	return &previewer{name: name, report: config.Report}
+}
+
+// writer is where the gcode is written: to out, and kept for the preview and the report.
+func (p *previewer) writer(out io.Writer) io.Writer {
+	if p.name == "" && p.report == configuration.ReportNone {
+		return out
+	}
+
+	return io.MultiWriter(out, &p.program)
+}
+
+// addSource adds the drawing of a file under the toolpath.
+func (p *previewer) addSource(file string, config configuration.Config) error {
+	if p.name == "" {
+		return nil
+	}
+
+	fileDesc, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	defer func(closer io.Closer) {
+		_ = closer.Close()
+	}(fileDesc)
+
+	return p.scene.AddSource(fileDesc, config)
+}
+
+// addBox adds a box under the toolpath.
+func (p *previewer) addBox(box geometry.Box) {
+	p.scene.AddBox(box)
+}
+
+// write writes the machining time report of the gcode to report, and renders its preview as SVG
+// and PNG files. A program the simulator cannot replay only fails the preview.
+func (p *previewer) write(report io.Writer, config configuration.Config) error {
+	if p.name == "" && p.report == configuration.ReportNone {
+		return nil
+	}
+
+	toolpath, err := simulator.Simulate(&p.program, config.RapidFeed, config.Post)
+	if err != nil && p.name != "" {
+		return err
+	}
+
+	if err != nil {
+		_, err := fmt.Fprintf(report, "; No time estimate: %s\n", err)
+
+		return err
+	}
+
+	if err := toolpath.Estimate(config.Profile, config.RapidFeed).Write(report, p.report); err != nil {
+		return err
+	}
+
+	if p.name == "" {
+		return nil
+	}
+
+	p.scene.AddToolpath(*toolpath)
+
+	if err := writeFile(p.name+".svg", p.scene.WriteSVG); err != nil {
+		return err
+	}
+
+	return writeFile(p.name+".png", func(out io.Writer) error {
+		return p.scene.WritePNG(out, previewSize)
+	})
+}
+
+func writeFile(name string, write func(io.Writer) error) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
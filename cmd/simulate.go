@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+func simulateCommand(files *[]string, config *configuration.Config) *cobra.Command {
+	var (
+		limits   = simulator.Limits{ArcTolerance: 0.01}
+		envelope geometry.Box
+	)
+
+	output := &cobra.Command{
+		Use:          "simulate <filename.nc>",
+		Short:        "Check gcode by simulating it",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("envelope") {
+				limits.Envelope = &envelope
+			}
+
+			var problems error
+
+			for _, file := range *files {
+				fileDesc, err := os.Open(file)
+				if err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+
+				defer func(closer io.Closer) {
+					_ = closer.Close()
+				}(fileDesc)
+
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s:\n", file); err != nil {
+					return err
+				}
+
+				err = simulator.Process(fileDesc, cmd.OutOrStdout(), *config, limits)
+
+				switch {
+				case errors.Is(err, simulator.ErrProblems):
+					problems = fmt.Errorf("%s: %w", file, err)
+				case err != nil:
+					return fmt.Errorf("%s: %w", file, err)
+				}
+			}
+
+			return problems
+		},
+	}
+
+	output.Flags().Float64VarP(&limits.StockTop, "stock-top", "", limits.StockTop, "Z of the top of the stock in millimeters")
+	output.Flags().VarP(&envelope, "envelope", "", "machine envelope [(minX, minY), (maxX, maxY)]")
+	output.Flags().Float64VarP(&limits.ArcTolerance, "arc-tolerance", "", limits.ArcTolerance, "max difference of the arc radius at both ends in millimeters")
+
+	return output
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/gcode"
 	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/simulator"
 	"github.com/landru29/cnc-drilling/internal/surfacer"
 	"github.com/spf13/cobra"
 )
@@ -18,7 +20,24 @@ func surfaceCommand(config *configuration.Config) *cobra.Command {
 		Use:   "surface",
 		Short: "Generate gcode to surface a rectangle area",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return surfacer.Process(surface, step, cmd.OutOrStdout(), cmd.OutOrStderr(), *config, method)
+			viewer := newPreviewer(*config)
+			viewer.addBox(surface)
+			guard := simulator.NewGuard(viewer.writer(cmd.OutOrStdout()), *config)
+			out := gcode.NewWriter(guard, config.Post, config.Spindle())
+
+			if err := surfacer.Process(surface, step, out, *config, method); err != nil {
+				return err
+			}
+
+			if err := out.Close(); err != nil {
+				return err
+			}
+
+			if err := guard.Close(); err != nil {
+				return err
+			}
+
+			return viewer.write(cmd.ErrOrStderr(), *config)
 		},
 	}
 
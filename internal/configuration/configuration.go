@@ -2,19 +2,83 @@ package configuration
 
 import (
 	"math"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
 )
 
 // Config is the main application configuration.
 type Config struct {
-	Feed         float64         `default:"60"     json:"feed"           mapstructure:"feed"          yaml:"feed"`
-	SecurityZ    float64         `default:"5"      json:"security_z"     mapstructure:"security_z"    yaml:"security_z"`
-	Deepness     float64         `default:"1"      json:"deepness"       mapstructure:"deepness"      yaml:"deepness"`
-	DeepPerTry   float64         `default:"0"      json:"deep_per_try"   mapstructure:"deep_per_try"  yaml:"deep_per_try"`
-	DeepStart    float64         `default:"0"      json:"deep_start"     mapstructure:"deep_start"    yaml:"deep_start"`
-	Layers       []string        `                 json:"layers"         mapstructure:"layers"        yaml:"layers"`
-	Origin       OriginDetection `                 json:"origin"         mapstructure:"origin"        yaml:"origin"`
-	BeforeScript string          `default:""       json:"before_script"  mapstructure:"before_script" yaml:"before_script"`
-	AfterScript  string          `default:"G0X0Y0" json:"after_script"   mapstructure:"after_script"  yaml:"after_script"`
+	Feed             float64            `default:"60"     json:"feed"              mapstructure:"feed"              yaml:"feed"`
+	SecurityZ        float64            `default:"5"      json:"security_z"        mapstructure:"security_z"        yaml:"security_z"`
+	Deepness         float64            `default:"1"      json:"deepness"          mapstructure:"deepness"          yaml:"deepness"`
+	DeepPerTry       float64            `default:"0"      json:"deep_per_try"      mapstructure:"deep_per_try"      yaml:"deep_per_try"`
+	DeepStart        float64            `default:"0"      json:"deep_start"        mapstructure:"deep_start"        yaml:"deep_start"`
+	Layers           []string           `                 json:"layers"            mapstructure:"layers"            yaml:"layers"`
+	Origin           OriginDetection    `                 json:"origin"            mapstructure:"origin"            yaml:"origin"`
+	BeforeScript     string             `default:""       json:"before_script"     mapstructure:"before_script"     yaml:"before_script"`
+	AfterScript      string             `default:"G0X0Y0" json:"after_script"      mapstructure:"after_script"      yaml:"after_script"`
+	ToolDiameter     float64            `default:"0"      json:"tool_diameter"     mapstructure:"tool_diameter"     yaml:"tool_diameter"`
+	Side             geometry.Side      `default:"on"     json:"side"              mapstructure:"side"              yaml:"side"`
+	TabCount         int                `default:"0"      json:"tab_count"         mapstructure:"tab_count"         yaml:"tab_count"`
+	TabSpacing       float64            `default:"0"      json:"tab_spacing"       mapstructure:"tab_spacing"       yaml:"tab_spacing"`
+	TabWidth         float64            `default:"5"      json:"tab_width"         mapstructure:"tab_width"         yaml:"tab_width"`
+	TabHeight        float64            `default:"0"      json:"tab_height"        mapstructure:"tab_height"        yaml:"tab_height"`
+	TabLayer         string             `default:""       json:"tab_layer"         mapstructure:"tab_layer"         yaml:"tab_layer"`
+	Cycle            gcode.Cycle        `default:"none"   json:"cycle"             mapstructure:"cycle"             yaml:"cycle"`
+	Circles          bool               `default:"false"  json:"circles"           mapstructure:"circles"           yaml:"circles"`
+	Optimize         bool               `default:"false"  json:"optimize"          mapstructure:"optimize"          yaml:"optimize"`
+	OptimizeTime     time.Duration      `default:"1s"     json:"optimize_time"     mapstructure:"optimize_time"     yaml:"optimize_time"`
+	Post             gcode.Post         `default:"none"   json:"post"              mapstructure:"post"              yaml:"post"`
+	SpindleSpeed     float64            `default:"0"      json:"spindle_speed"     mapstructure:"spindle_speed"     yaml:"spindle_speed"`
+	SpindleDirection gcode.Direction    `default:"cw"     json:"spindle_direction" mapstructure:"spindle_direction" yaml:"spindle_direction"`
+	SpindleDwell     time.Duration      `default:"0s"     json:"spindle_dwell"     mapstructure:"spindle_dwell"     yaml:"spindle_dwell"`
+	Coolant          gcode.Coolant      `default:"off"    json:"coolant"           mapstructure:"coolant"           yaml:"coolant"`
+	Laser            bool               `default:"false"  json:"laser"             mapstructure:"laser"             yaml:"laser"`
+	PlungeFeed       float64            `default:"0"      json:"plunge_feed"       mapstructure:"plunge_feed"       yaml:"plunge_feed"`
+	RetractFeed      float64            `default:"0"      json:"retract_feed"      mapstructure:"retract_feed"      yaml:"retract_feed"`
+	Clearance        float64            `default:"0"      json:"clearance"         mapstructure:"clearance"         yaml:"clearance"`
+	RapidFeed        float64            `default:"0"      json:"rapid_feed"        mapstructure:"rapid_feed"        yaml:"rapid_feed"`
+	Entry            gcode.Entry        `default:"plunge" json:"entry"             mapstructure:"entry"             yaml:"entry"`
+	RampAngle        float64            `default:"3"      json:"ramp_angle"        mapstructure:"ramp_angle"        yaml:"ramp_angle"`
+	Spiral           bool               `default:"false"  json:"spiral"            mapstructure:"spiral"            yaml:"spiral"`
+	InnerFirst       bool               `default:"false"  json:"inner_first"       mapstructure:"inner_first"       yaml:"inner_first"`
+	Order            Order              `default:"level"  json:"order"             mapstructure:"order"             yaml:"order"`
+	Milling          geometry.Milling   `default:"any"    json:"milling"           mapstructure:"milling"           yaml:"milling"`
+	Lead             geometry.Lead      `default:"none"   json:"lead"              mapstructure:"lead"              yaml:"lead"`
+	LeadLength       float64            `default:"2"      json:"lead_length"       mapstructure:"lead_length"       yaml:"lead_length"`
+	Overlap          float64            `default:"0"      json:"overlap"           mapstructure:"overlap"           yaml:"overlap"`
+	Preview          string             `default:""       json:"preview"           mapstructure:"preview"           yaml:"preview"`
+	Report           Report             `default:"text"   json:"report"            mapstructure:"report"            yaml:"report"`
+	Machine          string             `default:""       json:"machine"           mapstructure:"machine"           yaml:"machine"`
+	Machines         map[string]Machine `                 json:"machines"          mapstructure:"machines"          yaml:"machines"`
+	Profile          Machine            `                 json:"-"                 mapstructure:"-"                 yaml:"-"`
+}
+
+// Spindle is the spindle and coolant setup.
+func (c Config) Spindle() gcode.Spindle {
+	return gcode.Spindle{
+		Speed:     c.SpindleSpeed,
+		Direction: c.SpindleDirection,
+		Dwell:     c.SpindleDwell,
+		Coolant:   c.Coolant,
+		Laser:     c.Laser,
+	}
+}
+
+// PlungeRate is the feed moving the tool down in the material. It is the feed when not set.
+func (c Config) PlungeRate() float64 {
+	if c.PlungeFeed > 0 {
+		return c.PlungeFeed
+	}
+
+	return c.Feed
+}
+
+// Slope is the max slope of the tool going down along a path.
+func (c Config) Slope() float64 {
+	return math.Tan(c.RampAngle * math.Pi / 180)
 }
 
 // TryDeeps is the set of deeps during all tries.
@@ -0,0 +1,146 @@
+package configuration
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+)
+
+// defaultJunctionDeviation is the junction deviation of the profiles leaving it unset.
+const defaultJunctionDeviation = 0.01
+
+// Axis is the travel and the kinematic limits of an axis of the machine. The travel is left out when
+// its max is not above its min.
+type Axis struct {
+	Min          float64 `default:"0" json:"min"          mapstructure:"min"          yaml:"min"`
+	Max          float64 `default:"0" json:"max"          mapstructure:"max"          yaml:"max"`
+	MaxFeed      float64 `default:"0" json:"max_feed"     mapstructure:"max_feed"     yaml:"max_feed"`
+	MaxRate      float64 `default:"0" json:"max_rate"     mapstructure:"max_rate"     yaml:"max_rate"`
+	Acceleration float64 `default:"0" json:"acceleration" mapstructure:"acceleration" yaml:"acceleration"`
+}
+
+// Machine is the profile of the machine: travels in millimeters (in the coordinates of the program),
+// max cutting feeds and max rates (the rapid rates) in millimeters per minute, accelerations in
+// millimeters per second squared, and the junction deviation in millimeters (GRBL settings $130 to
+// $132, $110 to $112, $120 to $122 and $11). The limits left to 0 do not limit the moves. Codes
+// are the G-codes the controller supports (all when empty), and Post the post-processor used when
+// none is given.
+type Machine struct {
+	X                 Axis       `               json:"x"                  mapstructure:"x"                  yaml:"x"`
+	Y                 Axis       `               json:"y"                  mapstructure:"y"                  yaml:"y"`
+	Z                 Axis       `               json:"z"                  mapstructure:"z"                  yaml:"z"`
+	JunctionDeviation float64    `default:"0.01" json:"junction_deviation" mapstructure:"junction_deviation" yaml:"junction_deviation"`
+	SpindleMin        float64    `default:"0"    json:"spindle_min"        mapstructure:"spindle_min"        yaml:"spindle_min"`
+	SpindleMax        float64    `default:"0"    json:"spindle_max"        mapstructure:"spindle_max"        yaml:"spindle_max"`
+	Codes             []string   `               json:"codes"              mapstructure:"codes"              yaml:"codes"`
+	Post              gcode.Post `default:"none" json:"post"               mapstructure:"post"               yaml:"post"`
+}
+
+// Axes are the limits of the X, Y and Z axes.
+func (m Machine) Axes() [3]Axis {
+	return [3]Axis{m.X, m.Y, m.Z}
+}
+
+// Limits are the max rate (in millimeters per minute) and the acceleration (in millimeters per
+// second squared) along a unit direction. They are infinite when not limited.
+func (m Machine) Limits(direction [3]float64) (float64, float64) {
+	rate := math.Inf(1)
+	acceleration := math.Inf(1)
+
+	for idx, axis := range m.Axes() {
+		component := math.Abs(direction[idx])
+		if component < 1e-9 {
+			continue
+		}
+
+		if axis.MaxRate > 0 {
+			rate = math.Min(rate, axis.MaxRate/component)
+		}
+
+		if axis.Acceleration > 0 {
+			acceleration = math.Min(acceleration, axis.Acceleration/component)
+		}
+	}
+
+	return rate, acceleration
+}
+
+// Feed is the max cutting feed (in millimeters per minute) along a unit direction: the max feed of
+// the axes, or their max rate when not set. It is infinite when not limited.
+func (m Machine) Feed(direction [3]float64) float64 {
+	output := math.Inf(1)
+
+	for idx, axis := range m.Axes() {
+		component := math.Abs(direction[idx])
+		if component < 1e-9 {
+			continue
+		}
+
+		limit := axis.MaxFeed
+		if limit <= 0 {
+			limit = axis.MaxRate
+		}
+
+		if limit > 0 {
+			output = math.Min(output, limit/component)
+		}
+	}
+
+	return output
+}
+
+// Supports tells whether the controller supports a G-code.
+func (m Machine) Supports(code string) bool {
+	return len(m.Codes) == 0 || slices.Contains(m.Codes, code)
+}
+
+// CheckSpindle checks that a spindle speed is in the range of the machine. A speed of 0 leaves the
+// spindle alone.
+func (m Machine) CheckSpindle(speed float64) error {
+	if speed <= 0 {
+		return nil
+	}
+
+	if (m.SpindleMin > 0 && speed < m.SpindleMin) || (m.SpindleMax > 0 && speed > m.SpindleMax) {
+		return fmt.Errorf("spindle speed %g out of the range [%g, %g] of the machine", speed, m.SpindleMin, m.SpindleMax)
+	}
+
+	return nil
+}
+
+// SelectMachine sets Profile to the profile of the machine named by Machine, among Machines, and
+// checks the spindle speed against it. The post-processor of the profile is used unless one is given
+// (by a flag, the config file or the environment), even none. The profile has no limits when no name
+// is given.
+func (c *Config) SelectMachine(postGiven bool) error {
+	if c.Machine == "" {
+		return nil
+	}
+
+	profile, ok := c.Machines[c.Machine]
+	if !ok {
+		names := make([]string, 0, len(c.Machines))
+		for name := range c.Machines {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return fmt.Errorf("unknown machine: %s (known: %v)", c.Machine, names)
+	}
+
+	if profile.JunctionDeviation <= 0 {
+		profile.JunctionDeviation = defaultJunctionDeviation
+	}
+
+	if !postGiven {
+		c.Post = profile.Post
+	}
+
+	c.Profile = profile
+
+	return profile.CheckSpindle(c.SpindleSpeed)
+}
@@ -0,0 +1,94 @@
+package configuration
+
+import "fmt"
+
+// Order is the order of the passes over the paths or the holes.
+type Order int
+
+const (
+	// OrderLevel machines all the paths at one deep before going deeper.
+	OrderLevel Order = iota
+
+	// OrderDepth finishes each path to the full deep before moving to the next one.
+	OrderDepth
+)
+
+// Pass is the machining of one element (path or hole) during one try.
+type Pass struct {
+	Element int
+	Try     int
+}
+
+// Passes gives the passes over count elements, try after try, in the configured order.
+func (c Config) Passes(count int) []Pass {
+	tries := len(c.TryDeeps())
+	output := make([]Pass, 0, count*tries)
+
+	if c.Order == OrderDepth {
+		for element := range count {
+			for try := range tries {
+				output = append(output, Pass{Element: element, Try: try})
+			}
+		}
+
+		return output
+	}
+
+	for try := range tries {
+		for element := range count {
+			output = append(output, Pass{Element: element, Try: try})
+		}
+	}
+
+	return output
+}
+
+// PreviousDeep is the deep reached before a try.
+func (c Config) PreviousDeep(try int) float64 {
+	if try == 0 {
+		return c.DeepStart
+	}
+
+	return c.TryDeeps()[try-1]
+}
+
+// String implements the pflag.Value interface.
+func (o Order) String() string {
+	switch o {
+	case OrderLevel:
+		return "level"
+	case OrderDepth:
+		return "depth"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (o *Order) Set(value string) error {
+	switch value {
+	case "level", "":
+		*o = OrderLevel
+	case "depth":
+		*o = OrderDepth
+	default:
+		return fmt.Errorf("unknown order: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (o Order) Type() string {
+	return "order"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (o Order) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (o *Order) UnmarshalText(data []byte) error {
+	return o.Set(string(data))
+}
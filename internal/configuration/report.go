@@ -0,0 +1,62 @@
+package configuration
+
+import "fmt"
+
+// Report is the form of the machining time report.
+type Report int
+
+const (
+	// ReportText writes the report as text.
+	ReportText Report = iota
+
+	// ReportJSON writes the report as JSON.
+	ReportJSON
+
+	// ReportNone writes no report.
+	ReportNone
+)
+
+// String implements the pflag.Value interface.
+func (r Report) String() string {
+	switch r {
+	case ReportText:
+		return "text"
+	case ReportJSON:
+		return "json"
+	case ReportNone:
+		return "none"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (r *Report) Set(value string) error {
+	switch value {
+	case "text", "":
+		*r = ReportText
+	case "json":
+		*r = ReportJSON
+	case "none":
+		*r = ReportNone
+	default:
+		return fmt.Errorf("unknown report: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (r Report) Type() string {
+	return "report"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (r Report) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (r *Report) UnmarshalText(data []byte) error {
+	return r.Set(string(data))
+}
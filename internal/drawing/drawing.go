@@ -0,0 +1,124 @@
+package drawing
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"slices"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// Kind is the kind of a drawing entity.
+type Kind int
+
+const (
+	// KindPoint is a single point.
+	KindPoint Kind = iota
+
+	// KindVertex is a polyline vertex.
+	KindVertex
+
+	// KindLine is a straight line.
+	KindLine
+
+	// KindArc is an arc of circle.
+	KindArc
+
+	// KindCircle is a full circle.
+	KindCircle
+
+	// KindPolyline is a polyline.
+	KindPolyline
+
+	// KindLightPolyline is a light polyline.
+	KindLightPolyline
+
+	// KindPath is a free path, made of lines and arcs.
+	KindPath
+
+	// KindEllipse is an ellipse, or an elliptical arc, fitted with arcs.
+	KindEllipse
+
+	// KindSpline is a spline, fitted with arcs.
+	KindSpline
+
+	// KindText is a stroke of a text, drawn with the single-stroke font.
+	KindText
+)
+
+// tolerance is the chord tolerance used to approximate curves, in millimeters.
+const tolerance = 0.01
+
+// Entity is a drawing element, whatever the input format.
+type Entity struct {
+	Layer  string
+	Kind   Kind
+	Linker geometry.Linker
+}
+
+// Drawing is a set of entities spread on layers.
+// Drill files give the diameter of the tool of each layer.
+type Drawing struct {
+	Layers       []string
+	CurrentLayer string
+	Entities     []Entity
+	Tools        map[string]float64
+}
+
+// FromReader reads a drawing. The format is detected from the content.
+func FromReader(in io.Reader) (*Drawing, error) {
+	reader := bufio.NewReader(in)
+
+	head, _ := reader.Peek(512)
+
+	if isSVG(head) {
+		return fromSVG(reader)
+	}
+
+	if isExcellon(head) {
+		return fromExcellon(reader)
+	}
+
+	return fromDXF(reader)
+}
+
+// Filter selects the entities by layers (if specified).
+func (d Drawing) Filter(layers ...string) []Entity {
+	output := []Entity{}
+
+	for _, entity := range d.Entities {
+		if len(layers) > 0 && !slices.Contains(layers, entity.Layer) {
+			continue
+		}
+
+		output = append(output, entity)
+	}
+
+	return output
+}
+
+// Circle gives the center and the radius of a circle entity.
+func (e Entity) Circle() (geometry.Coordinates, float64, bool) {
+	if e.Kind != KindCircle {
+		return geometry.Coordinates{}, 0, false
+	}
+
+	path, ok := e.Linker.(*geometry.Path)
+	if !ok || len(*path) == 0 {
+		return geometry.Coordinates{}, 0, false
+	}
+
+	curve, ok := (*path)[0].(*geometry.Curve)
+	if !ok {
+		return geometry.Coordinates{}, 0, false
+	}
+
+	return curve.Center, curve.Radius, true
+}
+
+func isSVG(head []byte) bool {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+
+	return bytes.HasPrefix(trimmed, []byte("<"))
+}
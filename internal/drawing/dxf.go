@@ -0,0 +1,462 @@
+package drawing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/yofu/dxf"
+	dxfdrawing "github.com/yofu/dxf/drawing"
+	"github.com/yofu/dxf/entity"
+)
+
+// dxfSupported are the entities read by the DXF library. The other ones are read from their raw groups.
+var dxfSupported = map[string]bool{
+	"LINE":       true,
+	"3DFACE":     true,
+	"LWPOLYLINE": true,
+	"CIRCLE":     true,
+	"ARC":        true,
+	"VERTEX":     true,
+	"POINT":      true,
+}
+
+// dxfRecord is an entity with its raw groups (code and value), and the entity read by the DXF
+// library when it knows it.
+type dxfRecord struct {
+	kind   string
+	groups [][2]string
+	entity entity.Entity
+}
+
+// dxfFile is the content of a DXF file: its entities and its blocks.
+type dxfFile struct {
+	content string // the other sections, as read
+	records []*dxfRecord
+	blocks  map[string]*dxfBlock
+}
+
+// dxfBlock is a group of entities, placed with INSERT entities.
+type dxfBlock struct {
+	base    geometry.Coordinates
+	records []*dxfRecord
+}
+
+func fromDXF(in io.Reader) (*Drawing, error) {
+	content, err := splitDXF(in)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := content.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(closer io.Closer) {
+		_ = closer.Close()
+	}(file)
+
+	output := &Drawing{
+		CurrentLayer: file.CurrentLayer.Name(),
+	}
+
+	for name := range file.Layers {
+		output.Layers = append(output.Layers, name)
+	}
+
+	sort.Strings(output.Layers)
+
+	for idx, record := range content.records {
+		entities, err := content.entities(idx, record, identity, "", 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s #%d: %w", record.kind, idx, err)
+		}
+
+		output.Entities = append(output.Entities, entities...)
+	}
+
+	return output, nil
+}
+
+// splitDXF reads the raw groups of a DXF file. The entities and the blocks are taken apart from the
+// other sections.
+func splitDXF(in io.Reader) (*dxfFile, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		builder strings.Builder
+		section string
+		current *dxfRecord
+		blocks  []*dxfRecord
+		lines   int
+	)
+
+	output := &dxfFile{blocks: map[string]*dxfBlock{}}
+	groups := [][2]string{}
+
+	for scanner.Scan() {
+		code := strings.TrimSpace(scanner.Text())
+
+		if !scanner.Scan() {
+			return nil, fmt.Errorf("line %d: missing value for group code %s", lines+1, code)
+		}
+
+		groups = append(groups, [2]string{code, scanner.Text()})
+		lines += 2
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for idx, group := range groups {
+		name := strings.ToUpper(strings.TrimSpace(group[1]))
+
+		if group[0] == "0" {
+			current = nil
+
+			if name == "EOF" {
+				break
+			}
+
+			if name == "SECTION" && idx+1 < len(groups) && groups[idx+1][0] == "2" {
+				section = strings.ToUpper(strings.TrimSpace(groups[idx+1][1]))
+			}
+		}
+
+		if section != "ENTITIES" && section != "BLOCKS" {
+			if _, err := fmt.Fprintf(&builder, "%s\n%s\n", group[0], group[1]); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case group[0] == "0" && name == "ENDSEC":
+			section = ""
+		case group[0] == "0" && name != "SECTION":
+			current = &dxfRecord{kind: name}
+
+			if section == "ENTITIES" {
+				output.records = append(output.records, current)
+			} else {
+				blocks = append(blocks, current)
+			}
+		}
+
+		if current != nil {
+			current.groups = append(current.groups, group)
+		}
+	}
+
+	output.content = builder.String()
+	output.splitBlocks(blocks)
+
+	return output, nil
+}
+
+// splitBlocks gathers the entities of each block.
+func (f *dxfFile) splitBlocks(records []*dxfRecord) {
+	var current *dxfBlock
+
+	for _, record := range records {
+		switch record.kind {
+		case "BLOCK":
+			current = &dxfBlock{}
+
+			if base, _ := record.coordinates("10", "20"); len(base) > 0 {
+				current.base = base[0]
+			}
+
+			f.blocks[record.value("2")] = current
+		case "ENDBLK":
+			current = nil
+		default:
+			if current != nil {
+				current.records = append(current.records, record)
+			}
+		}
+	}
+}
+
+// parse reads the file with the DXF library. Each entity known by the library, in the file and in
+// the blocks, is read on its own from its raw groups, and attached to its record.
+func (f *dxfFile) parse() (*dxfdrawing.Drawing, error) {
+	file, err := dxf.FromStringData(f.content + "0\nSECTION\n2\nENTITIES\n0\nENDSEC\n0\nEOF\n")
+	if err != nil {
+		return nil, err
+	}
+
+	records := append([]*dxfRecord{}, f.records...)
+	for _, block := range f.blocks {
+		records = append(records, block.records...)
+	}
+
+	for _, record := range records {
+		if !dxfSupported[record.kind] {
+			continue
+		}
+
+		record.entity, err = dxf.ParseEntity(file, record.groups)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", record.kind, err)
+		}
+	}
+
+	return file, nil
+}
+
+// entities converts a record. Inside an insert, the entities are transformed, and those of the
+// layer 0 take the layer of the insert.
+func (f *dxfFile) entities(idx int, record *dxfRecord, transform matrix, parent string, depth int) ([]Entity, error) {
+	layer := record.value("8")
+	if record.entity != nil {
+		layer = record.entity.Layer().Name()
+	}
+
+	if layer == "" {
+		layer = defaultLayer
+	}
+
+	if parent != "" && layer == defaultLayer {
+		layer = parent
+	}
+
+	if record.kind == "INSERT" {
+		return f.insert(idx, record, transform, layer, depth)
+	}
+
+	name := fmt.Sprintf("#%d / Layer %s", idx, layer)
+
+	var (
+		linkers []geometry.Linker
+		kind    Kind
+	)
+
+	switch record.kind {
+	case "ELLIPSE":
+		kind = KindEllipse
+
+		path, err := record.ellipse(name)
+		if err != nil {
+			return nil, err
+		}
+
+		linkers = append(linkers, path)
+	case "SPLINE":
+		kind = KindSpline
+
+		path, err := record.spline(name)
+		if err != nil {
+			return nil, err
+		}
+
+		linkers = append(linkers, path)
+	case "TEXT", "MTEXT":
+		kind = KindText
+
+		paths, err := record.text(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			linkers = append(linkers, &path)
+		}
+	default:
+		var ok bool
+
+		if record.entity == nil {
+			return nil, nil
+		}
+
+		if kind, ok = dxfKind(record.entity); !ok {
+			return nil, nil
+		}
+
+		linkers = append(linkers, geometry.NewLinker(name, record.entity))
+	}
+
+	output := []Entity{}
+
+	for _, linker := range linkers {
+		if linker == nil {
+			continue
+		}
+
+		if path, ok := linker.(*geometry.Path); ok && len(*path) == 0 {
+			continue
+		}
+
+		linkerKind := kind
+
+		if transform != identity {
+			linker, linkerKind = transformLinker(name, kind, linker, transform)
+		}
+
+		output = append(output, Entity{
+			Layer:  layer,
+			Kind:   linkerKind,
+			Linker: linker,
+		})
+	}
+
+	return output, nil
+}
+
+func dxfKind(dxfEntity entity.Entity) (Kind, bool) {
+	switch dxfEntity.(type) {
+	case *entity.Point:
+		return KindPoint, true
+	case *entity.Vertex:
+		return KindVertex, true
+	case *entity.Line:
+		return KindLine, true
+	case *entity.Arc:
+		return KindArc, true
+	case *entity.Circle:
+		return KindCircle, true
+	case *entity.Polyline:
+		return KindPolyline, true
+	case *entity.LwPolyline:
+		return KindLightPolyline, true
+	}
+
+	return 0, false
+}
+
+// ellipse reads an ELLIPSE entity.
+func (r dxfRecord) ellipse(name string) (*geometry.Path, error) {
+	center, err := r.coordinates("10", "20")
+	if err != nil {
+		return nil, err
+	}
+
+	major, err := r.coordinates("11", "21")
+	if err != nil {
+		return nil, err
+	}
+
+	ratio, err := r.number("40", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := r.number("41", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := r.number("42", 2*math.Pi)
+	if err != nil {
+		return nil, err
+	}
+
+	normal, err := r.number("230", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(center) == 0 || len(major) == 0 {
+		return nil, fmt.Errorf("missing center or axis")
+	}
+
+	return geometry.NewPathFromEllipse(name, center[0], major[0], ratio, start, end, normal < 0, tolerance), nil
+}
+
+// spline reads a SPLINE entity.
+func (r dxfRecord) spline(name string) (*geometry.Path, error) {
+	degree, err := r.number("71", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	knots, err := r.numbers("40")
+	if err != nil {
+		return nil, err
+	}
+
+	weights, err := r.numbers("41")
+	if err != nil {
+		return nil, err
+	}
+
+	controls, err := r.coordinates("10", "20")
+	if err != nil {
+		return nil, err
+	}
+
+	fits, err := r.coordinates("11", "21")
+	if err != nil {
+		return nil, err
+	}
+
+	return geometry.NewPathFromSpline(name, int(degree), knots, controls, weights, fits, tolerance), nil
+}
+
+// value gives the first value of a group code.
+func (r dxfRecord) value(code string) string {
+	for _, group := range r.groups {
+		if group[0] == code {
+			return strings.TrimSpace(group[1])
+		}
+	}
+
+	return ""
+}
+
+// numbers gives all the values of a group code.
+func (r dxfRecord) numbers(code string) ([]float64, error) {
+	output := []float64{}
+
+	for _, group := range r.groups {
+		if group[0] != code {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(group[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for group code %s: %s", code, group[1])
+		}
+
+		output = append(output, value)
+	}
+
+	return output, nil
+}
+
+// number gives the first value of a group code, or the fallback when missing.
+func (r dxfRecord) number(code string, fallback float64) (float64, error) {
+	values, err := r.numbers(code)
+	if err != nil || len(values) == 0 {
+		return fallback, err
+	}
+
+	return values[0], nil
+}
+
+// coordinates pairs the values of the X and Y group codes.
+func (r dxfRecord) coordinates(codeX string, codeY string) ([]geometry.Coordinates, error) {
+	xs, err := r.numbers(codeX)
+	if err != nil {
+		return nil, err
+	}
+
+	ys, err := r.numbers(codeY)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]geometry.Coordinates, min(len(xs), len(ys)))
+	for idx := range output {
+		output[idx] = geometry.Coordinates{X: xs[idx], Y: ys[idx]}
+	}
+
+	return output, nil
+}
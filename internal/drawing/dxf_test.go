@@ -0,0 +1,316 @@
+package drawing_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/drawing"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dxfSample builds a DXF file from its entities, given as group code and value pairs.
+func dxfSample(entities ...string) string {
+	return dxfSampleWithBlocks(nil, entities...)
+}
+
+// dxfSampleWithBlocks builds a DXF file from its blocks and its entities.
+func dxfSampleWithBlocks(blocks []string, entities ...string) string {
+	groups := []string{}
+
+	if len(blocks) > 0 {
+		groups = append(append(append(groups, "0", "SECTION", "2", "BLOCKS"), blocks...), "0", "ENDSEC")
+	}
+
+	groups = append(append(append(groups, "0", "SECTION", "2", "ENTITIES"), entities...), "0", "ENDSEC", "0", "EOF")
+
+	return strings.Join(groups, "\n") + "\n"
+}
+
+func TestFromReaderDXFEllipseSpline(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(dxfSample(
+		"0", "LINE",
+		"8", "0",
+		"10", "0", "20", "0", "30", "0",
+		"11", "10", "21", "0", "31", "0",
+		"0", "ELLIPSE",
+		"8", "curves",
+		"10", "50", "20", "50", "30", "0",
+		"11", "20", "21", "0", "31", "0",
+		"40", "0.5",
+		"41", "0",
+		"42", "3.14159265358979",
+		"0", "SPLINE",
+		"8", "curves",
+		"70", "8",
+		"71", "3",
+		"72", "8",
+		"73", "4",
+		"40", "0", "40", "0", "40", "0", "40", "0",
+		"40", "1", "40", "1", "40", "1", "40", "1",
+		"10", "0", "20", "0", "30", "0",
+		"10", "10", "20", "20", "30", "0",
+		"10", "30", "20", "20", "30", "0",
+		"10", "40", "20", "0", "30", "0",
+		"0", "HATCH",
+		"8", "0",
+		"10", "0", "20", "0", "30", "0",
+		"2", "SOLID",
+	)))
+	require.NoError(t, err)
+
+	require.Len(t, input.Entities, 3)
+
+	assert.Equal(t, drawing.KindLine, input.Entities[0].Kind)
+
+	curves := input.Filter("curves")
+	require.Len(t, curves, 2)
+
+	assert.Equal(t, drawing.KindEllipse, curves[0].Kind)
+	assert.InDelta(t, 70, curves[0].Linker.Start().X, 1e-6)
+	assert.InDelta(t, 50, curves[0].Linker.Start().Y, 1e-6)
+	assert.InDelta(t, 30, curves[0].Linker.End().X, 1e-6)
+	assert.InDelta(t, 50, curves[0].Linker.End().Y, 1e-6)
+
+	box := curves[0].Linker.Box()
+	assert.InDelta(t, 60, box.Max.Y, 0.01)
+	assert.InDelta(t, 50, box.Min.Y, 0.01)
+
+	assert.Equal(t, drawing.KindSpline, curves[1].Kind)
+	assert.Equal(t, geometry.Coordinates{X: 0, Y: 0}, *curves[1].Linker.Start())
+	assert.InDelta(t, 40, curves[1].Linker.End().X, 1e-9)
+	assert.InDelta(t, 15, curves[1].Linker.Box().Max.Y, 0.01)
+}
+
+func TestFromReaderDXFInsert(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(dxfSampleWithBlocks(
+		[]string{
+			"0", "BLOCK",
+			"8", "0",
+			"2", "hole",
+			"10", "1", "20", "1", "30", "0",
+			"0", "CIRCLE",
+			"8", "0",
+			"10", "1", "20", "1", "30", "0",
+			"40", "1",
+			"0", "POINT",
+			"8", "0",
+			"10", "1", "20", "1", "30", "0",
+			"0", "ENDBLK",
+			"8", "0",
+			"0", "BLOCK",
+			"8", "0",
+			"2", "pair",
+			"10", "0", "20", "0", "30", "0",
+			"0", "INSERT",
+			"8", "0",
+			"2", "hole",
+			"10", "0", "20", "0", "30", "0",
+			"0", "INSERT",
+			"8", "0",
+			"2", "hole",
+			"10", "10", "20", "0", "30", "0",
+			"0", "ENDBLK",
+			"8", "0",
+		},
+		"0", "INSERT",
+		"8", "holes",
+		"2", "pair",
+		"10", "100", "20", "100", "30", "0",
+		"41", "2",
+		"42", "2",
+		"50", "90",
+		"70", "1",
+		"71", "2",
+		"45", "50",
+	)))
+	require.NoError(t, err)
+
+	holes := input.Filter("holes")
+	require.Len(t, holes, 8)
+
+	centers := []geometry.Coordinates{}
+
+	for _, hole := range holes {
+		if hole.Kind == drawing.KindPoint {
+			assert.Contains(t, []float64{100, 50}, math.Round(hole.Linker.Start().X))
+
+			continue
+		}
+
+		center, radius, ok := hole.Circle()
+		require.True(t, ok)
+		assert.InDelta(t, 2, radius, 1e-9)
+
+		centers = append(centers, geometry.Coordinates{X: math.Round(center.X*1e6) / 1e6, Y: math.Round(center.Y*1e6) / 1e6})
+	}
+
+	// Rows go along the rotated Y axis, that is along -X.
+	assert.ElementsMatch(t, []geometry.Coordinates{
+		{X: 100, Y: 100},
+		{X: 100, Y: 120},
+		{X: 50, Y: 100},
+		{X: 50, Y: 120},
+	}, centers)
+
+}
+
+func TestFromReaderDXFPolylineBefore(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(dxfSampleWithBlocks(
+		[]string{
+			"0", "BLOCK",
+			"8", "0",
+			"2", "hole",
+			"10", "0", "20", "0", "30", "0",
+			"0", "CIRCLE",
+			"8", "0",
+			"10", "0", "20", "0", "30", "0",
+			"40", "1",
+			"0", "ENDBLK",
+			"8", "0",
+		},
+		"0", "POLYLINE",
+		"8", "0",
+		"66", "1",
+		"0", "VERTEX",
+		"8", "0",
+		"10", "0", "20", "0", "30", "0",
+		"0", "VERTEX",
+		"8", "0",
+		"10", "5", "20", "5", "30", "0",
+		"0", "SEQEND",
+		"8", "0",
+		"0", "ELLIPSE",
+		"8", "curves",
+		"10", "50", "20", "50", "30", "0",
+		"11", "20", "21", "0", "31", "0",
+		"40", "0.5",
+		"41", "0",
+		"42", "3.14159265358979",
+		"0", "LINE",
+		"8", "0",
+		"10", "0", "20", "0", "30", "0",
+		"11", "10", "21", "0", "31", "0",
+		"0", "SPLINE",
+		"8", "curves",
+		"70", "8",
+		"71", "3",
+		"72", "8",
+		"73", "4",
+		"40", "0", "40", "0", "40", "0", "40", "0",
+		"40", "1", "40", "1", "40", "1", "40", "1",
+		"10", "0", "20", "0", "30", "0",
+		"10", "10", "20", "20", "30", "0",
+		"10", "30", "20", "20", "30", "0",
+		"10", "40", "20", "0", "30", "0",
+		"0", "INSERT",
+		"8", "holes",
+		"2", "hole",
+		"10", "100", "20", "100", "30", "0",
+		"41", "2",
+		"42", "2",
+	)))
+	require.NoError(t, err)
+
+	lines := []drawing.Entity{}
+
+	for _, entity := range input.Entities {
+		if entity.Kind == drawing.KindLine {
+			lines = append(lines, entity)
+		}
+	}
+
+	require.Len(t, lines, 1)
+	assert.Equal(t, geometry.Coordinates{X: 10, Y: 0}, *lines[0].Linker.End())
+
+	curves := input.Filter("curves")
+	require.Len(t, curves, 2)
+	assert.Equal(t, drawing.KindEllipse, curves[0].Kind)
+	assert.InDelta(t, 70, curves[0].Linker.Start().X, 1e-6)
+	assert.Equal(t, drawing.KindSpline, curves[1].Kind)
+	assert.InDelta(t, 40, curves[1].Linker.End().X, 1e-9)
+
+	holes := input.Filter("holes")
+	require.Len(t, holes, 1)
+
+	center, radius, ok := holes[0].Circle()
+	require.True(t, ok)
+	assert.InDelta(t, 2, radius, 1e-9)
+	assert.InDelta(t, 100, center.X, 1e-9)
+	assert.InDelta(t, 100, center.Y, 1e-9)
+}
+
+func TestFromReaderDXFInsertUnknownBlock(t *testing.T) {
+	_, err := drawing.FromReader(strings.NewReader(dxfSample(
+		"0", "INSERT",
+		"8", "0",
+		"2", "missing",
+		"10", "0", "20", "0", "30", "0",
+	)))
+	assert.Error(t, err)
+}
+
+func TestFromReaderDXFText(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(dxfSample(
+		"0", "TEXT",
+		"8", "labels",
+		"10", "0", "20", "0", "30", "0",
+		"40", "21",
+		"1", "%%uI",
+		"72", "2",
+		"11", "100", "21", "0", "31", "0",
+		"0", "MTEXT",
+		"8", "labels",
+		"10", "0", "20", "100", "30", "0",
+		"40", "21",
+		"71", "1",
+		"1", "{\\fArial;I}\\PI",
+	)))
+	require.NoError(t, err)
+
+	labels := input.Filter("labels")
+	require.Len(t, labels, 3)
+
+	for _, label := range labels {
+		assert.Equal(t, drawing.KindText, label.Kind)
+	}
+
+	// Right aligned on its second point.
+	assert.Equal(t, geometry.Coordinates{X: 96, Y: 21}, *labels[0].Linker.Start())
+
+	// Attached by its top left corner, on two lines.
+	assert.Equal(t, geometry.Coordinates{X: 4, Y: 100}, *labels[1].Linker.Start())
+	assert.Equal(t, geometry.Coordinates{X: 4, Y: 65}, *labels[2].Linker.Start())
+}
+
+func TestFromReaderDXFTextRotation(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(dxfSample(
+		"0", "TEXT",
+		"8", "text",
+		"10", "0", "20", "0", "30", "0",
+		"40", "21",
+		"50", "90",
+		"1", "I",
+		"0", "MTEXT",
+		"8", "mtext",
+		"10", "0", "20", "0", "30", "0",
+		"40", "21",
+		"50", "1.5707963267948966",
+		"71", "7",
+		"1", "I",
+	)))
+	require.NoError(t, err)
+
+	text := input.Filter("text")
+	mtext := input.Filter("mtext")
+	require.Len(t, text, 1)
+	require.Len(t, mtext, 1)
+
+	// Both are turned by a quarter: the stroke of the I goes along X.
+	for _, label := range []drawing.Entity{text[0], mtext[0]} {
+		assert.InDelta(t, label.Linker.Start().Y, label.Linker.End().Y, 1e-9)
+		assert.Greater(t, math.Abs(label.Linker.Start().X-label.Linker.End().X), 10.0)
+	}
+}
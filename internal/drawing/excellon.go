@@ -0,0 +1,279 @@
+package drawing
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// excellonFormat is the way coordinates without decimal point are written.
+type excellonFormat struct {
+	unit     float64
+	integers int
+	decimals int
+	leading  bool
+}
+
+// coordinate reads an Excellon coordinate, in millimeters.
+func (f excellonFormat) coordinate(value string) (float64, error) {
+	if strings.Contains(value, ".") {
+		output, err := strconv.ParseFloat(value, 64)
+
+		return output * f.unit, err
+	}
+
+	sign := ""
+	digits := value
+
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign = digits[:1]
+		digits = digits[1:]
+	}
+
+	// With leading zeros kept, the digits are read from the left. Otherwise, from the right.
+	if f.leading {
+		for len(digits) < f.integers+f.decimals {
+			digits += "0"
+		}
+	}
+
+	output, err := strconv.ParseFloat(sign+digits, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if f.leading {
+		return output / math.Pow10(len(digits)-f.integers) * f.unit, nil
+	}
+
+	return output / math.Pow10(f.decimals) * f.unit, nil
+}
+
+// setUnit reads the unit command (METRIC or INCH) with its zero suppression and format.
+func (f *excellonFormat) setUnit(line string) {
+	parts := strings.Split(line, ",")
+
+	f.unit = 1
+	f.integers = 3
+	f.decimals = 3
+
+	if parts[0] == "INCH" {
+		f.unit = 25.4
+		f.integers = 2
+		f.decimals = 4
+	}
+
+	for _, part := range parts[1:] {
+		switch {
+		case part == "LZ":
+			f.leading = true
+		case part == "TZ":
+			f.leading = false
+		case strings.Contains(part, "."):
+			pieces := strings.SplitN(part, ".", 2)
+			f.integers = len(pieces[0])
+			f.decimals = len(pieces[1])
+		}
+	}
+}
+
+func isExcellon(head []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(head))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		return line == "M48"
+	}
+
+	return false
+}
+
+// fromExcellon reads an Excellon drill file. Each tool is a layer, named after the tool.
+// Holes are points, and slots are lines. A tool defined after the header is selected too.
+func fromExcellon(in io.Reader) (*Drawing, error) {
+	output := &Drawing{
+		Tools: map[string]float64{},
+	}
+
+	format := excellonFormat{unit: 1, integers: 3, decimals: 3}
+	scanner := bufio.NewScanner(in)
+	tool := ""
+	header := false
+	position := geometry.Coordinates{}
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.ToUpper(strings.TrimSpace(scanner.Text()))
+
+		if strings.HasPrefix(line, ";") {
+			if value, ok := strings.CutPrefix(line, ";FILE_FORMAT="); ok {
+				if _, err := fmt.Sscanf(value, "%d:%d", &format.integers, &format.decimals); err != nil {
+					return nil, fmt.Errorf("line %d: invalid file format: %w", lineNumber, err)
+				}
+			}
+
+			continue
+		}
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "METRIC"), strings.HasPrefix(line, "INCH"):
+			format.setUnit(line)
+		case line == "M48":
+			header = true
+		case line == "%", line == "M95":
+			header = false
+		case line == "M71":
+			format.setUnit("METRIC")
+		case line == "M72":
+			format.setUnit("INCH")
+		case strings.HasPrefix(line, "T"):
+			name, diameter, err := excellonTool(line, format)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			if diameter > 0 {
+				if _, ok := output.Tools[name]; !ok {
+					output.Layers = append(output.Layers, name)
+				}
+
+				output.Tools[name] = diameter
+
+				if header {
+					continue
+				}
+			}
+
+			tool = name
+		case strings.HasPrefix(line, "X"), strings.HasPrefix(line, "Y"):
+			start, end, slot := line, "", false
+			if before, after, found := strings.Cut(line, "G85"); found {
+				start, end, slot = before, after, true
+			}
+
+			from, err := excellonPosition(start, position, format)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			position = from
+
+			if _, ok := output.Tools[tool]; !ok {
+				return nil, fmt.Errorf("line %d: no tool selected", lineNumber)
+			}
+
+			name := fmt.Sprintf("#%d / Layer %s", len(output.Entities), tool)
+
+			if !slot {
+				output.Entities = append(output.Entities, Entity{
+					Layer:  tool,
+					Kind:   KindPoint,
+					Linker: geometry.Point{Name: name, Coordinates: from},
+				})
+
+				continue
+			}
+
+			to, err := excellonPosition(end, position, format)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			position = to
+
+			output.Entities = append(output.Entities, Entity{
+				Layer:  tool,
+				Kind:   KindLine,
+				Linker: &geometry.Segment{Name: name, StartPoint: from, EndPoint: to},
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(output.Layers) > 0 {
+		output.CurrentLayer = output.Layers[0]
+	}
+
+	return output, nil
+}
+
+// excellonTool reads a tool definition (T1C0.8) or a tool selection (T1).
+func excellonTool(line string, format excellonFormat) (string, float64, error) {
+	end := 1
+	for end < len(line) && line[end] >= '0' && line[end] <= '9' {
+		end++
+	}
+
+	number, err := strconv.Atoi(line[1:end])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid tool: %s", line)
+	}
+
+	name := fmt.Sprintf("T%d", number)
+
+	index := strings.Index(line, "C")
+	if index < 0 {
+		return name, 0, nil
+	}
+
+	value := line[index+1:]
+	if stop := strings.IndexAny(value, "FSBHZ"); stop >= 0 {
+		value = value[:stop]
+	}
+
+	diameter, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid tool diameter: %s", line)
+	}
+
+	return name, diameter * format.unit, nil
+}
+
+// excellonPosition reads X and Y coordinates. A missing coordinate keeps its previous value.
+func excellonPosition(value string, previous geometry.Coordinates, format excellonFormat) (geometry.Coordinates, error) {
+	output := previous
+
+	for value != "" {
+		axis := value[0]
+
+		end := 1
+		for end < len(value) && strings.IndexByte("0123456789.+-", value[end]) >= 0 {
+			end++
+		}
+
+		number, err := format.coordinate(value[1:end])
+		if err != nil {
+			return output, fmt.Errorf("invalid coordinate: %s", value)
+		}
+
+		switch axis {
+		case 'X':
+			output.X = number
+		case 'Y':
+			output.Y = number
+		default:
+			return output, fmt.Errorf("unexpected %c in coordinates", axis)
+		}
+
+		value = value[end:]
+	}
+
+	return output, nil
+}
@@ -0,0 +1,76 @@
+package drawing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/drawing"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromReaderExcellon(t *testing.T) {
+	t.Run("inch with leading zeros", func(t *testing.T) {
+		input, err := drawing.FromReader(strings.NewReader("M48\nINCH,LZ\nT1C0.0315\nT02F00S00C0.04\n%\nT1\nX012500Y005\nY01\nT2\nX01Y01G85X02Y01\nM30\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"T1", "T2"}, input.Layers)
+		assert.InDelta(t, 0.8001, input.Tools["T1"], 1e-9)
+		assert.InDelta(t, 1.016, input.Tools["T2"], 1e-9)
+
+		holes := input.Filter("T1")
+		require.Len(t, holes, 2)
+		assert.Equal(t, drawing.KindPoint, holes[0].Kind)
+		assert.InDelta(t, 31.75, holes[0].Linker.Start().X, 1e-9)
+		assert.InDelta(t, 12.7, holes[0].Linker.Start().Y, 1e-9)
+		assert.InDelta(t, 31.75, holes[1].Linker.Start().X, 1e-9)
+		assert.InDelta(t, 25.4, holes[1].Linker.Start().Y, 1e-9)
+
+		slots := input.Filter("T2")
+		require.Len(t, slots, 1)
+		assert.Equal(t, drawing.KindLine, slots[0].Kind)
+		assert.Equal(t, geometry.Coordinates{X: 25.4, Y: 25.4}, *slots[0].Linker.Start())
+		assert.Equal(t, geometry.Coordinates{X: 50.8, Y: 25.4}, *slots[0].Linker.End())
+	})
+
+	t.Run("metric with trailing zeros", func(t *testing.T) {
+		input, err := drawing.FromReader(strings.NewReader("; header\nM48\nMETRIC,TZ\nT1C0.8\n%\nT1\nX12500Y-3000\nX1.5Y2.5\nM30\n"))
+		require.NoError(t, err)
+
+		holes := input.Filter("T1")
+		require.Len(t, holes, 2)
+		assert.Equal(t, geometry.Coordinates{X: 12.5, Y: -3}, *holes[0].Linker.Start())
+		assert.Equal(t, geometry.Coordinates{X: 1.5, Y: 2.5}, *holes[1].Linker.Start())
+	})
+
+	t.Run("unit switch in the body", func(t *testing.T) {
+		input, err := drawing.FromReader(strings.NewReader("M48\nMETRIC,TZ,000.00\nT1C0.8\n%\nT1\nM72\nX10000Y5000\nM71\nX1000Y2000\nM30\n"))
+		require.NoError(t, err)
+
+		holes := input.Filter("T1")
+		require.Len(t, holes, 2)
+		assert.InDelta(t, 25.4, holes[0].Linker.Start().X, 1e-9)
+		assert.InDelta(t, 12.7, holes[0].Linker.Start().Y, 1e-9)
+		assert.InDelta(t, 1, holes[1].Linker.Start().X, 1e-9)
+		assert.InDelta(t, 2, holes[1].Linker.Start().Y, 1e-9)
+	})
+
+	t.Run("tool defined in the body", func(t *testing.T) {
+		input, err := drawing.FromReader(strings.NewReader("M48\nMETRIC\nT1C0.8\n%\nT2C1.0\nX1.0Y1.0\nT1\nX2.0Y2.0\nM30\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"T1", "T2"}, input.Layers)
+		assert.InDelta(t, 1, input.Tools["T2"], 1e-9)
+
+		holes := input.Filter("T2")
+		require.Len(t, holes, 1)
+		assert.Equal(t, geometry.Coordinates{X: 1, Y: 1}, *holes[0].Linker.Start())
+		assert.Len(t, input.Filter("T1"), 1)
+	})
+
+	t.Run("no tool", func(t *testing.T) {
+		_, err := drawing.FromReader(strings.NewReader("M48\nMETRIC\n%\nX1.0Y1.0\nM30\n"))
+		assert.Error(t, err)
+	})
+}
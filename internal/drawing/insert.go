@@ -0,0 +1,159 @@
+package drawing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// maxInsertDepth limits the nesting of blocks, as a block inserting itself would never end.
+const maxInsertDepth = 16
+
+// insert expands an INSERT entity: the entities of its block are placed at each cell of its array.
+func (f *dxfFile) insert(idx int, record *dxfRecord, transform matrix, layer string, depth int) ([]Entity, error) {
+	if depth >= maxInsertDepth {
+		return nil, fmt.Errorf("blocks nested deeper than %d", maxInsertDepth)
+	}
+
+	block, ok := f.blocks[record.value("2")]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %s", record.value("2"))
+	}
+
+	placements, err := record.placements(block.base)
+	if err != nil {
+		return nil, err
+	}
+
+	output := []Entity{}
+
+	for _, placement := range placements {
+		for _, child := range block.records {
+			entities, err := f.entities(idx, child, transform.multiply(placement), layer, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			output = append(output, entities...)
+		}
+	}
+
+	return output, nil
+}
+
+// placements gives the transforms of an INSERT entity, one for each cell of its array.
+func (r dxfRecord) placements(base geometry.Coordinates) ([]matrix, error) {
+	values := map[string]float64{}
+
+	for code, fallback := range map[string]float64{
+		"10": 0, "20": 0, "41": 1, "42": 1, "50": 0, "70": 1, "71": 1, "44": 0, "45": 0, "230": 1,
+	} {
+		value, err := r.number(code, fallback)
+		if err != nil {
+			return nil, err
+		}
+
+		values[code] = value
+	}
+
+	angle := values["50"] * math.Pi / 180
+	cos, sin := math.Cos(angle), math.Sin(angle)
+
+	// The block is scaled and rotated around its base point.
+	shape := matrix{cos, sin, -sin, cos, 0, 0}.
+		multiply(matrix{values["41"], 0, 0, values["42"], 0, 0}).
+		multiply(matrix{1, 0, 0, 1, -base.X, -base.Y})
+
+	// An insert seen from below (extrusion going down) is mirrored.
+	mirror := identity
+	if values["230"] < 0 {
+		mirror = matrix{-1, 0, 0, 1, 0, 0}
+	}
+
+	output := []matrix{}
+
+	for row := range max(1, int(values["71"])) {
+		for column := range max(1, int(values["70"])) {
+			offsetX := float64(column) * values["44"]
+			offsetY := float64(row) * values["45"]
+
+			translation := matrix{
+				1, 0, 0, 1,
+				values["10"] + cos*offsetX - sin*offsetY,
+				values["20"] + sin*offsetX + cos*offsetY,
+			}
+
+			output = append(output, mirror.multiply(translation).multiply(shape))
+		}
+	}
+
+	return output, nil
+}
+
+// transformLinker moves an entity of a block to its place. Arcs and circles stay so when the
+// transform keeps the shapes, and become ellipses otherwise.
+func transformLinker(name string, kind Kind, linker geometry.Linker, transform matrix) (geometry.Linker, Kind) {
+	switch value := linker.(type) {
+	case geometry.Point:
+		return geometry.Point{Name: name, Coordinates: transform.apply(value.Coordinates)}, kind
+	case *geometry.Point:
+		return &geometry.Point{Name: name, Coordinates: transform.apply(value.Coordinates)}, kind
+	case *geometry.Segment:
+		return &geometry.Segment{
+			Name:       name,
+			StartPoint: transform.apply(value.StartPoint),
+			EndPoint:   transform.apply(value.EndPoint),
+		}, kind
+	case *geometry.Curve:
+		path := transformPath(name, geometry.Path{value}, transform)
+		if len(path) == 1 {
+			if curve, ok := path[0].(*geometry.Curve); ok {
+				return curve, kind
+			}
+		}
+
+		return &path, KindEllipse
+	case *geometry.Path:
+		path := transformPath(name, *value, transform)
+
+		if _, ok := transform.similarity(); !ok && kind == KindCircle {
+			kind = KindEllipse
+		}
+
+		return &path, kind
+	}
+
+	return linker, kind
+}
+
+// transformPath draws a path through the transform.
+func transformPath(name string, path geometry.Path, transform matrix) geometry.Path {
+	builder := &pathBuilder{
+		name:      name,
+		transform: transform,
+	}
+
+	for idx, element := range path {
+		if idx == 0 || !builder.current.Equal(*element.Start()) {
+			builder.moveTo(*element.Start())
+		}
+
+		switch value := element.(type) {
+		case *geometry.Curve:
+			sweep := value.Sweep()
+			builder.arcTo(value.Radius, value.Radius, 0, math.Abs(sweep) > math.Pi, sweep > 0, value.EndPoint)
+		default:
+			builder.lineTo(*element.End())
+		}
+	}
+
+	builder.flush()
+
+	output := geometry.Path{}
+	for _, part := range builder.paths {
+		output = append(output, *part...)
+	}
+
+	return output
+}
@@ -0,0 +1,383 @@
+package drawing
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+const (
+	// pixel is the size of a SVG user unit without explicit unit, in millimeters (96 dpi).
+	pixel = 25.4 / 96
+
+	// defaultLayer is the layer of the shapes out of any group, as in DXF.
+	defaultLayer = "0"
+)
+
+// unitSizes are the sizes of the SVG units, in millimeters.
+var unitSizes = map[string]float64{
+	"":   pixel,
+	"px": pixel,
+	"mm": 1,
+	"cm": 10,
+	"q":  0.25,
+	"in": 25.4,
+	"pt": 25.4 / 72,
+	"pc": 25.4 / 6,
+}
+
+// hiddenElements are the SVG elements whose content is not drawn directly.
+var hiddenElements = map[string]bool{
+	"defs":     true,
+	"clipPath": true,
+	"mask":     true,
+	"marker":   true,
+	"pattern":  true,
+	"symbol":   true,
+	"metadata": true,
+}
+
+// svgState is the context inherited by the SVG elements.
+type svgState struct {
+	transform matrix
+	layer     string
+	labelled  bool
+	hidden    bool
+}
+
+func fromSVG(in io.Reader) (*Drawing, error) {
+	decoder := xml.NewDecoder(in)
+
+	output := &Drawing{CurrentLayer: defaultLayer}
+	layers := map[string]bool{}
+	stack := []svgState{}
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			state := svgState{transform: identity, layer: defaultLayer}
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+			}
+
+			attributes := svgAttributes(element)
+
+			if element.Name.Local == "svg" && len(stack) == 0 {
+				state.transform, err = viewport(attributes)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if value, ok := attributes["transform"]; ok {
+				transform, err := parseTransform(value)
+				if err != nil {
+					return nil, err
+				}
+
+				state.transform = state.transform.multiply(transform)
+			}
+
+			if hiddenElements[element.Name.Local] {
+				state.hidden = true
+			}
+
+			if element.Name.Local == "g" {
+				switch {
+				case attributes["inkscape:label"] != "":
+					state.layer = attributes["inkscape:label"]
+					state.labelled = true
+				case attributes["id"] != "" && !state.labelled:
+					state.layer = attributes["id"]
+				}
+			}
+
+			stack = append(stack, state)
+
+			if state.hidden {
+				continue
+			}
+
+			entities, err := svgShape(element.Name.Local, attributes, state, len(output.Entities))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", element.Name.Local, err)
+			}
+
+			if len(entities) > 0 && !layers[state.layer] {
+				layers[state.layer] = true
+				output.Layers = append(output.Layers, state.layer)
+			}
+
+			output.Entities = append(output.Entities, entities...)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if len(output.Layers) == 0 {
+		output.Layers = []string{defaultLayer}
+	}
+
+	return output, nil
+}
+
+// svgAttributes indexes the attributes by name. Inkscape attributes are prefixed with "inkscape:".
+func svgAttributes(element xml.StartElement) map[string]string {
+	output := map[string]string{}
+
+	for _, attribute := range element.Attr {
+		name := attribute.Name.Local
+
+		if strings.Contains(attribute.Name.Space, "inkscape") {
+			name = "inkscape:" + name
+		}
+
+		output[name] = attribute.Value
+	}
+
+	return output
+}
+
+// viewport computes the transform from the SVG user space to millimeters, with the Y axis going up.
+func viewport(attributes map[string]string) (matrix, error) {
+	width, widthUnit, err := parseLength(attributes["width"])
+	if err != nil {
+		return identity, fmt.Errorf("width: %w", err)
+	}
+
+	height, heightUnit, err := parseLength(attributes["height"])
+	if err != nil {
+		return identity, fmt.Errorf("height: %w", err)
+	}
+
+	viewBox, err := parseNumbers(attributes["viewBox"])
+	if err != nil {
+		return identity, fmt.Errorf("viewBox: %w", err)
+	}
+
+	scaleX := pixel
+	scaleY := pixel
+	top := height * heightUnit
+
+	if len(viewBox) == 4 && viewBox[2] > 0 && viewBox[3] > 0 {
+		if width > 0 {
+			scaleX = width * widthUnit / viewBox[2]
+		}
+
+		if height > 0 {
+			scaleY = height * heightUnit / viewBox[3]
+		}
+
+		if width > 0 && height <= 0 {
+			scaleY = scaleX
+		}
+
+		if height > 0 && width <= 0 {
+			scaleX = scaleY
+		}
+
+		top = viewBox[3] * scaleY
+
+		return matrix{scaleX, 0, 0, -scaleY, -viewBox[0] * scaleX, top + viewBox[1]*scaleY}, nil
+	}
+
+	return matrix{scaleX, 0, 0, -scaleY, 0, top}, nil
+}
+
+// parseLength reads a length with its unit, and gives the size of the unit in millimeters.
+// Relative lengths (percentages) are ignored.
+func parseLength(value string) (float64, float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasSuffix(value, "%") {
+		return 0, pixel, nil
+	}
+
+	number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	unit, ok := unitSizes[strings.ToLower(value[len(number):])]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown unit in %s", value)
+	}
+
+	output, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid length %s", value)
+	}
+
+	return output, unit, nil
+}
+
+// svgNumber reads a numeric attribute of a shape, in user units. Lengths with an absolute unit (mm,
+// cm, in, pt...) are converted at 96 user units per inch. Missing attributes are 0.
+func svgNumber(attributes map[string]string, name string) (float64, error) {
+	value := strings.TrimSpace(attributes[name])
+	if value == "" {
+		return 0, nil
+	}
+
+	if strings.HasSuffix(value, "%") {
+		return 0, fmt.Errorf("relative %s not supported: %s", name, value)
+	}
+
+	output, unit, err := parseLength(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return output * unit / pixel, nil
+}
+
+// svgShape converts a SVG shape into entities.
+func svgShape(name string, attributes map[string]string, state svgState, index int) ([]Entity, error) {
+	switch name {
+	case "path", "line", "polyline", "polygon", "rect", "circle", "ellipse":
+	default:
+		return nil, nil
+	}
+
+	numbers := map[string]float64{}
+
+	for _, attribute := range []string{"x", "y", "width", "height", "rx", "ry", "cx", "cy", "r", "x1", "y1", "x2", "y2"} {
+		value, err := svgNumber(attributes, attribute)
+		if err != nil {
+			return nil, err
+		}
+
+		numbers[attribute] = value
+	}
+
+	builder := &pathBuilder{
+		name:      fmt.Sprintf("#%d / Layer %s", index, state.layer),
+		transform: state.transform,
+	}
+
+	kind := KindPath
+
+	switch name {
+	case "path":
+		if err := builder.pathData(attributes["d"]); err != nil {
+			return nil, err
+		}
+	case "line":
+		kind = KindLine
+
+		builder.moveTo(geometry.Coordinates{X: numbers["x1"], Y: numbers["y1"]})
+		builder.lineTo(geometry.Coordinates{X: numbers["x2"], Y: numbers["y2"]})
+	case "polyline", "polygon":
+		kind = KindPolyline
+
+		points, err := parseNumbers(attributes["points"])
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := 0; idx+1 < len(points); idx += 2 {
+			point := geometry.Coordinates{X: points[idx], Y: points[idx+1]}
+
+			if idx == 0 {
+				builder.moveTo(point)
+
+				continue
+			}
+
+			builder.lineTo(point)
+		}
+
+		if name == "polygon" {
+			builder.close()
+		}
+	case "rect":
+		kind = KindPolyline
+
+		rect(builder, numbers)
+	case "circle":
+		if _, ok := state.transform.similarity(); ok {
+			kind = KindCircle
+		}
+
+		ellipse(builder, numbers["cx"], numbers["cy"], numbers["r"], numbers["r"])
+	case "ellipse":
+		ellipse(builder, numbers["cx"], numbers["cy"], numbers["rx"], numbers["ry"])
+	}
+
+	builder.flush()
+
+	output := make([]Entity, 0, len(builder.paths))
+
+	for _, path := range builder.paths {
+		var linker geometry.Linker = path
+
+		if kind == KindLine && len(*path) == 1 {
+			linker = (*path)[0]
+		}
+
+		output = append(output, Entity{
+			Layer:  state.layer,
+			Kind:   kind,
+			Linker: linker,
+		})
+	}
+
+	return output, nil
+}
+
+// rect draws a rectangle, with rounded corners when rx or ry are set.
+func rect(builder *pathBuilder, numbers map[string]float64) {
+	x, y := numbers["x"], numbers["y"]
+	width, height := numbers["width"], numbers["height"]
+
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	radiusX, radiusY := numbers["rx"], numbers["ry"]
+
+	switch {
+	case radiusX <= 0:
+		radiusX = radiusY
+	case radiusY <= 0:
+		radiusY = radiusX
+	}
+
+	radiusX = min(radiusX, width/2)
+	radiusY = min(radiusY, height/2)
+
+	builder.moveTo(geometry.Coordinates{X: x + radiusX, Y: y})
+	builder.lineTo(geometry.Coordinates{X: x + width - radiusX, Y: y})
+	builder.arcTo(radiusX, radiusY, 0, false, true, geometry.Coordinates{X: x + width, Y: y + radiusY})
+	builder.lineTo(geometry.Coordinates{X: x + width, Y: y + height - radiusY})
+	builder.arcTo(radiusX, radiusY, 0, false, true, geometry.Coordinates{X: x + width - radiusX, Y: y + height})
+	builder.lineTo(geometry.Coordinates{X: x + radiusX, Y: y + height})
+	builder.arcTo(radiusX, radiusY, 0, false, true, geometry.Coordinates{X: x, Y: y + height - radiusY})
+	builder.lineTo(geometry.Coordinates{X: x, Y: y + radiusY})
+	builder.arcTo(radiusX, radiusY, 0, false, true, geometry.Coordinates{X: x + radiusX, Y: y})
+	builder.close()
+}
+
+// ellipse draws an ellipse with two half arcs, as the circles of DXF.
+func ellipse(builder *pathBuilder, centerX float64, centerY float64, radiusX float64, radiusY float64) {
+	if radiusX <= 0 || radiusY <= 0 {
+		return
+	}
+
+	builder.moveTo(geometry.Coordinates{X: centerX + radiusX, Y: centerY})
+	builder.arcTo(radiusX, radiusY, 0, false, true, geometry.Coordinates{X: centerX - radiusX, Y: centerY})
+	builder.arcTo(radiusX, radiusY, 0, false, true, geometry.Coordinates{X: centerX + radiusX, Y: centerY})
+	builder.close()
+}
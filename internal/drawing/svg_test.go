@@ -0,0 +1,96 @@
+package drawing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/drawing"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape"
+     width="100mm" height="50mm" viewBox="0 0 200 100">
+  <defs>
+    <rect id="hidden" x="0" y="0" width="10" height="10"/>
+  </defs>
+  <g inkscape:label="outline" inkscape:groupmode="layer">
+    <rect x="20" y="20" width="40" height="20"/>
+    <g id="nested" transform="translate(100,0)">
+      <circle cx="20" cy="50" r="10"/>
+    </g>
+  </g>
+  <g id="holes">
+    <path d="M 140 20 A 10 10 0 0 1 160 20 L 160 40 C 160 50 140 50 140 40 z"/>
+    <line x1="0" y1="100" x2="200" y2="0" transform="scale(1, 0.5)"/>
+  </g>
+</svg>`
+
+func TestFromReaderSVG(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(sample))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"outline", "holes"}, input.Layers)
+
+	outline := input.Filter("outline")
+	require.Len(t, outline, 2)
+
+	assert.Equal(t, drawing.KindPolyline, outline[0].Kind)
+	assert.Equal(t, geometry.Box{
+		Min: geometry.Coordinates{X: 10, Y: 30},
+		Max: geometry.Coordinates{X: 30, Y: 40},
+	}, outline[0].Linker.Box())
+
+	center, radius, ok := outline[1].Circle()
+	require.True(t, ok)
+	assert.InDelta(t, 60, center.X, 1e-9)
+	assert.InDelta(t, 25, center.Y, 1e-9)
+	assert.InDelta(t, 5, radius, 1e-9)
+
+	holes := input.Filter("holes")
+	require.Len(t, holes, 2)
+
+	path, ok := holes[0].Linker.(*geometry.Path)
+	require.True(t, ok)
+	assert.True(t, path.IsClosed())
+
+	curve, ok := (*path)[0].(*geometry.Curve)
+	require.True(t, ok)
+	assert.InDelta(t, 5, curve.Radius, 1e-9)
+	assert.InDelta(t, 75, curve.Center.X, 1e-9)
+	assert.InDelta(t, 40, curve.Center.Y, 1e-9)
+	assert.False(t, curve.Clockwise)
+
+	assert.Equal(t, drawing.KindLine, holes[1].Kind)
+	assert.Equal(t, geometry.Coordinates{X: 0, Y: 25}, *holes[1].Linker.Start())
+	assert.Equal(t, geometry.Coordinates{X: 100, Y: 50}, *holes[1].Linker.End())
+}
+
+func TestFromReaderSVGInvalid(t *testing.T) {
+	_, err := drawing.FromReader(strings.NewReader(`<svg><path d="M 0 0 L 10"/></svg>`))
+	assert.Error(t, err)
+}
+
+func TestFromReaderSVGUnits(t *testing.T) {
+	input, err := drawing.FromReader(strings.NewReader(`<svg>
+<line x1="0" y1="0" x2="1in" y2="0"/>
+<line x1="10mm" y1="0" x2="2cm" y2="0"/>
+<line x1="0" y1="0" x2="72pt" y2="96px"/>
+</svg>`))
+	require.NoError(t, err)
+	require.Len(t, input.Entities, 3)
+
+	assert.InDelta(t, 25.4, input.Entities[0].Linker.End().X, 1e-9)
+	assert.InDelta(t, 10, input.Entities[1].Linker.Start().X, 1e-9)
+	assert.InDelta(t, 20, input.Entities[1].Linker.End().X, 1e-9)
+	assert.InDelta(t, 25.4, input.Entities[2].Linker.End().X, 1e-9)
+	assert.InDelta(t, -25.4, input.Entities[2].Linker.End().Y, 1e-9)
+
+	_, err = drawing.FromReader(strings.NewReader(`<svg><rect x="0" y="0" width="50%" height="10"/></svg>`))
+	assert.ErrorContains(t, err, "relative width")
+
+	_, err = drawing.FromReader(strings.NewReader(`<svg><rect x="0" y="0" width="10em" height="10"/></svg>`))
+	assert.ErrorContains(t, err, "unknown unit")
+}
@@ -0,0 +1,475 @@
+package drawing
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// pathScanner reads the numbers and commands of SVG path data.
+type pathScanner struct {
+	data string
+	pos  int
+}
+
+func (s *pathScanner) skipSeparators() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', ',', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// hasNumber checks whether a number comes next.
+func (s *pathScanner) hasNumber() bool {
+	s.skipSeparators()
+
+	if s.pos >= len(s.data) {
+		return false
+	}
+
+	switch character := s.data[s.pos]; {
+	case character >= '0' && character <= '9', character == '-', character == '+', character == '.':
+		return true
+	}
+
+	return false
+}
+
+// command reads a command letter when it comes next.
+func (s *pathScanner) command() (byte, bool) {
+	s.skipSeparators()
+
+	if s.pos >= len(s.data) {
+		return 0, false
+	}
+
+	character := s.data[s.pos]
+	if (character < 'a' || character > 'z') && (character < 'A' || character > 'Z') {
+		return 0, false
+	}
+
+	s.pos++
+
+	return character, true
+}
+
+// number reads a number. A sign or a second dot starts the next number.
+func (s *pathScanner) number() (float64, error) {
+	s.skipSeparators()
+
+	start := s.pos
+
+	if s.pos < len(s.data) && (s.data[s.pos] == '-' || s.data[s.pos] == '+') {
+		s.pos++
+	}
+
+	dot := false
+	exponent := false
+
+	for s.pos < len(s.data) {
+		character := s.data[s.pos]
+
+		switch {
+		case character >= '0' && character <= '9':
+		case character == '.' && !dot && !exponent:
+			dot = true
+		case (character == 'e' || character == 'E') && !exponent && s.pos > start:
+			exponent = true
+
+			if s.pos+1 < len(s.data) && (s.data[s.pos+1] == '-' || s.data[s.pos+1] == '+') {
+				s.pos++
+			}
+		default:
+			return s.parse(start)
+		}
+
+		s.pos++
+	}
+
+	return s.parse(start)
+}
+
+func (s *pathScanner) parse(start int) (float64, error) {
+	output, err := strconv.ParseFloat(s.data[start:s.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number at %d: %q", start, s.data[start:s.pos])
+	}
+
+	return output, nil
+}
+
+// flag reads an arc flag, which may be stuck to the next number.
+func (s *pathScanner) flag() (bool, error) {
+	s.skipSeparators()
+
+	if s.pos >= len(s.data) || (s.data[s.pos] != '0' && s.data[s.pos] != '1') {
+		return false, fmt.Errorf("invalid flag at %d", s.pos)
+	}
+
+	s.pos++
+
+	return s.data[s.pos-1] == '1', nil
+}
+
+// numbers reads count numbers.
+func (s *pathScanner) numbers(count int) ([]float64, error) {
+	output := make([]float64, count)
+
+	for idx := range output {
+		value, err := s.number()
+		if err != nil {
+			return nil, err
+		}
+
+		output[idx] = value
+	}
+
+	return output, nil
+}
+
+// pathBuilder builds the transformed subpaths of a SVG shape.
+type pathBuilder struct {
+	name      string
+	transform matrix
+	current   geometry.Coordinates
+	start     geometry.Coordinates
+	control   geometry.Coordinates
+	smooth    byte
+	path      geometry.Path
+	paths     []*geometry.Path
+}
+
+func (b *pathBuilder) flush() {
+	if len(b.path) > 0 {
+		path := b.path
+		b.paths = append(b.paths, &path)
+	}
+
+	b.path = geometry.Path{}
+}
+
+func (b *pathBuilder) moveTo(point geometry.Coordinates) {
+	b.flush()
+
+	b.current = point
+	b.start = point
+	b.smooth = 0
+}
+
+func (b *pathBuilder) lineTo(point geometry.Coordinates) {
+	from := b.transform.apply(b.current)
+	to := b.transform.apply(point)
+
+	if !from.Equal(to) {
+		b.path = append(b.path, &geometry.Segment{
+			Name:       b.name,
+			StartPoint: from,
+			EndPoint:   to,
+		})
+	}
+
+	b.current = point
+	b.smooth = 0
+}
+
+func (b *pathBuilder) close() {
+	b.lineTo(b.start)
+	b.flush()
+}
+
+// polyline approximates a parametric curve, given in the user space, with segments.
+func (b *pathBuilder) polyline(count int, at func(position float64) geometry.Coordinates) {
+	end := at(1)
+
+	for idx := 1; idx < count; idx++ {
+		b.lineTo(at(float64(idx) / float64(count)))
+	}
+
+	b.lineTo(end)
+}
+
+// cubicTo approximates a cubic Bézier curve with segments.
+func (b *pathBuilder) cubicTo(first geometry.Coordinates, second geometry.Coordinates, point geometry.Coordinates) {
+	start := b.current
+
+	points := []geometry.Coordinates{
+		b.transform.apply(start),
+		b.transform.apply(first),
+		b.transform.apply(second),
+		b.transform.apply(point),
+	}
+
+	bending := math.Max(
+		math.Hypot(points[0].X-2*points[1].X+points[2].X, points[0].Y-2*points[1].Y+points[2].Y),
+		math.Hypot(points[1].X-2*points[2].X+points[3].X, points[1].Y-2*points[2].Y+points[3].Y),
+	)
+
+	b.polyline(segmentCount(6*bending), func(position float64) geometry.Coordinates {
+		rest := 1 - position
+
+		return geometry.Coordinates{
+			X: rest*rest*rest*start.X + 3*rest*rest*position*first.X + 3*rest*position*position*second.X + position*position*position*point.X,
+			Y: rest*rest*rest*start.Y + 3*rest*rest*position*first.Y + 3*rest*position*position*second.Y + position*position*position*point.Y,
+		}
+	})
+
+	b.control = second
+	b.smooth = 'C'
+}
+
+// quadTo approximates a quadratic Bézier curve with segments.
+func (b *pathBuilder) quadTo(control geometry.Coordinates, point geometry.Coordinates) {
+	start := b.current
+
+	points := []geometry.Coordinates{
+		b.transform.apply(start),
+		b.transform.apply(control),
+		b.transform.apply(point),
+	}
+
+	bending := math.Hypot(points[0].X-2*points[1].X+points[2].X, points[0].Y-2*points[1].Y+points[2].Y)
+
+	b.polyline(segmentCount(2*bending), func(position float64) geometry.Coordinates {
+		rest := 1 - position
+
+		return geometry.Coordinates{
+			X: rest*rest*start.X + 2*rest*position*control.X + position*position*point.X,
+			Y: rest*rest*start.Y + 2*rest*position*control.Y + position*position*point.Y,
+		}
+	})
+
+	b.control = control
+	b.smooth = 'Q'
+}
+
+// segmentCount gives the number of segments keeping a curve, whose second derivative is bounded,
+// within the tolerance.
+func segmentCount(secondDerivative float64) int {
+	return max(1, int(math.Ceil(math.Sqrt(secondDerivative/(8*tolerance)))))
+}
+
+// arcTo draws an elliptical arc. It stays an arc of circle when the transform allows it,
+// and is approximated with segments otherwise.
+func (b *pathBuilder) arcTo(radiusX float64, radiusY float64, rotation float64, large bool, sweep bool, point geometry.Coordinates) {
+	start := b.current
+
+	radiusX = math.Abs(radiusX)
+	radiusY = math.Abs(radiusY)
+
+	if radiusX == 0 || radiusY == 0 || start.Equal(point) {
+		b.lineTo(point)
+
+		return
+	}
+
+	cos := math.Cos(rotation * math.Pi / 180)
+	sin := math.Sin(rotation * math.Pi / 180)
+
+	halfX := (start.X - point.X) / 2
+	halfY := (start.Y - point.Y) / 2
+	primeX := cos*halfX + sin*halfY
+	primeY := -sin*halfX + cos*halfY
+
+	if lambda := primeX*primeX/(radiusX*radiusX) + primeY*primeY/(radiusY*radiusY); lambda > 1 {
+		radiusX *= math.Sqrt(lambda)
+		radiusY *= math.Sqrt(lambda)
+	}
+
+	numerator := radiusX*radiusX*radiusY*radiusY - radiusX*radiusX*primeY*primeY - radiusY*radiusY*primeX*primeX
+	denominator := radiusX*radiusX*primeY*primeY + radiusY*radiusY*primeX*primeX
+
+	coefficient := math.Sqrt(math.Max(0, numerator/denominator))
+	if large == sweep {
+		coefficient = -coefficient
+	}
+
+	centerPrimeX := coefficient * radiusX * primeY / radiusY
+	centerPrimeY := -coefficient * radiusY * primeX / radiusX
+
+	center := geometry.Coordinates{
+		X: cos*centerPrimeX - sin*centerPrimeY + (start.X+point.X)/2,
+		Y: sin*centerPrimeX + cos*centerPrimeY + (start.Y+point.Y)/2,
+	}
+
+	startAngle := math.Atan2((primeY-centerPrimeY)/radiusY, (primeX-centerPrimeX)/radiusX)
+	endAngle := math.Atan2((-primeY-centerPrimeY)/radiusY, (-primeX-centerPrimeX)/radiusX)
+
+	delta := endAngle - startAngle
+
+	switch {
+	case sweep && delta < 0:
+		delta += 2 * math.Pi
+	case !sweep && delta > 0:
+		delta -= 2 * math.Pi
+	}
+
+	if scale, ok := b.transform.similarity(); ok && math.Abs(radiusX-radiusY) < 1e-9*radiusX {
+		b.path = append(b.path, &geometry.Curve{
+			Name:       b.name,
+			StartPoint: b.transform.apply(start),
+			EndPoint:   b.transform.apply(point),
+			Center:     b.transform.apply(center),
+			Radius:     radiusX * scale,
+			Clockwise:  (delta > 0) != (b.transform.determinant() < 0),
+		})
+
+		b.current = point
+		b.smooth = 0
+
+		return
+	}
+
+	radius := math.Max(radiusX, radiusY) * b.transform.stretch()
+	count := max(1, int(math.Ceil(math.Abs(delta)/(2*math.Acos(math.Max(0, 1-tolerance/radius))))))
+
+	b.polyline(count, func(position float64) geometry.Coordinates {
+		angle := startAngle + delta*position
+
+		return geometry.Coordinates{
+			X: center.X + cos*radiusX*math.Cos(angle) - sin*radiusY*math.Sin(angle),
+			Y: center.Y + sin*radiusX*math.Cos(angle) + cos*radiusY*math.Sin(angle),
+		}
+	})
+
+	b.current = point
+	b.smooth = 0
+}
+
+// pathData draws SVG path data.
+func (b *pathBuilder) pathData(data string) error {
+	scanner := pathScanner{data: data}
+
+	var command byte
+
+	for {
+		if letter, ok := scanner.command(); ok {
+			command = letter
+		} else if !scanner.hasNumber() {
+			break
+		} else if command == 0 || command == 'Z' || command == 'z' {
+			return fmt.Errorf("unexpected number in path data: %s", data)
+		}
+
+		relative := command >= 'a'
+		origin := geometry.Coordinates{}
+
+		if relative {
+			origin = b.current
+		}
+
+		point := func(values []float64) geometry.Coordinates {
+			return geometry.Coordinates{X: origin.X + values[0], Y: origin.Y + values[1]}
+		}
+
+		switch command {
+		case 'M', 'm':
+			values, err := scanner.numbers(2)
+			if err != nil {
+				return err
+			}
+
+			b.moveTo(point(values))
+
+			command -= 'M' - 'L'
+		case 'L', 'l':
+			values, err := scanner.numbers(2)
+			if err != nil {
+				return err
+			}
+
+			b.lineTo(point(values))
+		case 'H', 'h':
+			values, err := scanner.numbers(1)
+			if err != nil {
+				return err
+			}
+
+			b.lineTo(geometry.Coordinates{X: origin.X + values[0], Y: b.current.Y})
+		case 'V', 'v':
+			values, err := scanner.numbers(1)
+			if err != nil {
+				return err
+			}
+
+			b.lineTo(geometry.Coordinates{X: b.current.X, Y: origin.Y + values[0]})
+		case 'C', 'c':
+			values, err := scanner.numbers(6)
+			if err != nil {
+				return err
+			}
+
+			b.cubicTo(point(values), point(values[2:]), point(values[4:]))
+		case 'S', 's':
+			values, err := scanner.numbers(4)
+			if err != nil {
+				return err
+			}
+
+			b.cubicTo(b.reflected('C'), point(values), point(values[2:]))
+		case 'Q', 'q':
+			values, err := scanner.numbers(4)
+			if err != nil {
+				return err
+			}
+
+			b.quadTo(point(values), point(values[2:]))
+		case 'T', 't':
+			values, err := scanner.numbers(2)
+			if err != nil {
+				return err
+			}
+
+			b.quadTo(b.reflected('Q'), point(values))
+		case 'A', 'a':
+			radii, err := scanner.numbers(3)
+			if err != nil {
+				return err
+			}
+
+			large, err := scanner.flag()
+			if err != nil {
+				return err
+			}
+
+			sweep, err := scanner.flag()
+			if err != nil {
+				return err
+			}
+
+			values, err := scanner.numbers(2)
+			if err != nil {
+				return err
+			}
+
+			b.arcTo(radii[0], radii[1], radii[2], large, sweep, point(values))
+		case 'Z', 'z':
+			b.close()
+			b.current = b.start
+		default:
+			return fmt.Errorf("unknown path command: %c", command)
+		}
+	}
+
+	b.flush()
+
+	return nil
+}
+
+// reflected is the reflection of the last control point, used by the smooth curves.
+// It is the current point when the previous command is not a curve of the same kind.
+func (b *pathBuilder) reflected(kind byte) geometry.Coordinates {
+	if b.smooth != kind {
+		return b.current
+	}
+
+	return geometry.Coordinates{
+		X: 2*b.current.X - b.control.X,
+		Y: 2*b.current.Y - b.control.Y,
+	}
+}
@@ -0,0 +1,185 @@
+package drawing
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/font"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// textAlignment reads the alignment of a TEXT entity: horizontal (group code 72) and vertical
+// (group code 73, whose values match the font vertical alignments).
+func textAlignment(horizontal int, vertical int) (font.Alignment, font.VerticalAlignment) {
+	verticalAlignment := font.VerticalAlignment(min(max(vertical, 0), 3))
+
+	switch horizontal {
+	case 1:
+		return font.AlignCenter, verticalAlignment
+	case 2:
+		return font.AlignRight, verticalAlignment
+	case 4:
+		return font.AlignCenter, font.VerticalMiddle
+	}
+
+	return font.AlignLeft, verticalAlignment
+}
+
+// text draws a TEXT or a MTEXT entity with the single-stroke font.
+func (r dxfRecord) text(name string) ([]geometry.Path, error) {
+	height, err := r.number("40", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation, err := r.number("50", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := r.coordinates("10", "20")
+	if err != nil {
+		return nil, err
+	}
+
+	style := font.Style{
+		Height:   height,
+		Rotation: rotation,
+	}
+
+	if len(positions) > 0 {
+		style.Position = positions[0]
+	}
+
+	if r.kind == "MTEXT" {
+		return r.mtext(name, style)
+	}
+
+	horizontal, err := r.number("72", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	vertical, err := r.number("73", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	style.Align, style.Vertical = textAlignment(int(horizontal), int(vertical))
+
+	// Aligned texts are placed on their second point.
+	if aligned, err := r.coordinates("11", "21"); err == nil && len(aligned) > 0 && (horizontal != 0 || vertical != 0) {
+		style.Position = aligned[0]
+	}
+
+	return font.Render(name, textPlain(r.value("1")), style), nil
+}
+
+// mtext draws a MTEXT entity. Its attachment point (group code 71) gives its alignment. Unlike the
+// TEXT entities, its rotation (group code 50) is in radians.
+func (r dxfRecord) mtext(name string, style font.Style) ([]geometry.Path, error) {
+	style.Rotation = style.Rotation * 180 / math.Pi
+
+	attachment, err := r.number("71", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment = min(max(attachment, 1), 9) - 1
+
+	style.Align = font.Alignment(int(attachment) % 3)
+	style.Vertical = []font.VerticalAlignment{
+		font.VerticalTop,
+		font.VerticalMiddle,
+		font.VerticalBottom,
+	}[int(attachment)/3]
+
+	if direction, err := r.coordinates("11", "21"); err == nil && len(direction) > 0 {
+		style.Rotation = math.Atan2(direction[0].Y, direction[0].X) * 180 / math.Pi
+	}
+
+	// Long texts are split into chunks (group code 3), followed by the last one (group code 1).
+	var content strings.Builder
+
+	for _, group := range r.groups {
+		if group[0] == "3" {
+			content.WriteString(group[1])
+		}
+	}
+
+	content.WriteString(r.value("1"))
+
+	return font.Render(name, mtextPlain(content.String()), style), nil
+}
+
+// textPlain removes the control codes of a TEXT entity: %%nnn is a character code, %%% is a percent
+// sign, and the other ones (underline, overline, symbols) are dropped.
+func textPlain(value string) string {
+	var output strings.Builder
+
+	for idx := 0; idx < len(value); idx++ {
+		if !strings.HasPrefix(value[idx:], "%%") || idx+2 >= len(value) {
+			output.WriteByte(value[idx])
+
+			continue
+		}
+
+		if value[idx+2] == '%' {
+			output.WriteByte('%')
+		} else if idx+5 <= len(value) {
+			if number, err := strconv.Atoi(value[idx+2 : idx+5]); err == nil {
+				output.WriteRune(rune(number))
+
+				idx += 2
+			}
+		}
+
+		idx += 2
+	}
+
+	return output.String()
+}
+
+// mtextPlain removes the formatting codes of a MTEXT entity. Paragraphs (\P) become line feeds.
+func mtextPlain(value string) string {
+	var output strings.Builder
+
+	for idx := 0; idx < len(value); idx++ {
+		character := value[idx]
+
+		switch {
+		case character == '{' || character == '}':
+			continue
+		case character != '\\' || idx+1 >= len(value):
+			output.WriteByte(character)
+
+			continue
+		}
+
+		idx++
+
+		switch code := value[idx]; code {
+		case 'P':
+			output.WriteByte('\n')
+		case '~':
+			output.WriteByte(' ')
+		case '\\', '{', '}':
+			output.WriteByte(code)
+		case 'S':
+			end := strings.IndexByte(value[idx:], ';')
+			if end < 0 {
+				end = len(value) - idx
+			}
+
+			output.WriteString(strings.NewReplacer("^", "/", "#", "/").Replace(value[idx+1 : idx+end]))
+			idx += end
+		case 'A', 'C', 'c', 'F', 'f', 'H', 'Q', 'T', 'W', 'p':
+			if end := strings.IndexByte(value[idx:], ';'); end >= 0 {
+				idx += end
+			}
+		}
+	}
+
+	return output.String()
+}
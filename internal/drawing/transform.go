@@ -0,0 +1,151 @@
+package drawing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// matrix is an affine transform: x' = a.x + c.y + e and y' = b.x + d.y + f.
+type matrix [6]float64
+
+// identity is the transform keeping points in place.
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// multiply composes the transforms: other is applied first.
+func (m matrix) multiply(other matrix) matrix {
+	return matrix{
+		m[0]*other[0] + m[2]*other[1],
+		m[1]*other[0] + m[3]*other[1],
+		m[0]*other[2] + m[2]*other[3],
+		m[1]*other[2] + m[3]*other[3],
+		m[0]*other[4] + m[2]*other[5] + m[4],
+		m[1]*other[4] + m[3]*other[5] + m[5],
+	}
+}
+
+// apply transforms the point.
+func (m matrix) apply(point geometry.Coordinates) geometry.Coordinates {
+	return geometry.Coordinates{
+		X: m[0]*point.X + m[2]*point.Y + m[4],
+		Y: m[1]*point.X + m[3]*point.Y + m[5],
+	}
+}
+
+// determinant is negative when the transform mirrors the drawing.
+func (m matrix) determinant() float64 {
+	return m[0]*m[3] - m[1]*m[2]
+}
+
+// similarity gives the scale of a transform keeping the shapes (circles stay circles).
+func (m matrix) similarity() (float64, bool) {
+	first := math.Hypot(m[0], m[1])
+	second := math.Hypot(m[2], m[3])
+
+	if math.Abs(first-second) > 1e-9*math.Max(first, 1) || math.Abs(m[0]*m[2]+m[1]*m[3]) > 1e-9*math.Max(first*second, 1) {
+		return 0, false
+	}
+
+	return first, true
+}
+
+// stretch is an upper bound of the scale applied by the transform.
+func (m matrix) stretch() float64 {
+	return math.Sqrt(m[0]*m[0] + m[1]*m[1] + m[2]*m[2] + m[3]*m[3])
+}
+
+// parseTransform reads a SVG transform attribute.
+func parseTransform(value string) (matrix, error) {
+	output := identity
+	rest := strings.TrimSpace(value)
+
+	for rest != "" {
+		open := strings.Index(rest, "(")
+		closing := strings.Index(rest, ")")
+
+		if open < 0 || closing < open {
+			return identity, fmt.Errorf("invalid transform: %s", value)
+		}
+
+		name := strings.TrimSpace(strings.Trim(rest[:open], " ,\t\n\r"))
+
+		arguments, err := parseNumbers(rest[open+1 : closing])
+		if err != nil {
+			return identity, fmt.Errorf("invalid transform %s: %w", value, err)
+		}
+
+		current, err := transformFunction(name, arguments)
+		if err != nil {
+			return identity, err
+		}
+
+		output = output.multiply(current)
+		rest = strings.TrimSpace(strings.TrimLeft(rest[closing+1:], " ,\t\n\r"))
+	}
+
+	return output, nil
+}
+
+func transformFunction(name string, arguments []float64) (matrix, error) {
+	argument := func(index int, fallback float64) float64 {
+		if index < len(arguments) {
+			return arguments[index]
+		}
+
+		return fallback
+	}
+
+	if len(arguments) == 0 {
+		return identity, fmt.Errorf("transform %s without arguments", name)
+	}
+
+	switch name {
+	case "matrix":
+		if len(arguments) != 6 {
+			return identity, fmt.Errorf("transform matrix needs 6 arguments, got %d", len(arguments))
+		}
+
+		return matrix{arguments[0], arguments[1], arguments[2], arguments[3], arguments[4], arguments[5]}, nil
+	case "translate":
+		return matrix{1, 0, 0, 1, arguments[0], argument(1, 0)}, nil
+	case "scale":
+		return matrix{arguments[0], 0, 0, argument(1, arguments[0]), 0, 0}, nil
+	case "rotate":
+		angle := arguments[0] * math.Pi / 180
+		centerX := argument(1, 0)
+		centerY := argument(2, 0)
+
+		return matrix{1, 0, 0, 1, centerX, centerY}.
+			multiply(matrix{math.Cos(angle), math.Sin(angle), -math.Sin(angle), math.Cos(angle), 0, 0}).
+			multiply(matrix{1, 0, 0, 1, -centerX, -centerY}), nil
+	case "skewX":
+		return matrix{1, 0, math.Tan(arguments[0] * math.Pi / 180), 1, 0, 0}, nil
+	case "skewY":
+		return matrix{1, math.Tan(arguments[0] * math.Pi / 180), 0, 1, 0, 0}, nil
+	}
+
+	return identity, fmt.Errorf("unknown transform: %s", name)
+}
+
+// parseNumbers reads a list of numbers separated by spaces or commas.
+func parseNumbers(value string) ([]float64, error) {
+	scanner := pathScanner{data: value}
+	output := []float64{}
+
+	for scanner.hasNumber() {
+		number, err := scanner.number()
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, number)
+	}
+
+	if scanner.skipSeparators(); scanner.pos < len(scanner.data) {
+		return nil, fmt.Errorf("unexpected character %q", scanner.data[scanner.pos])
+	}
+
+	return output, nil
+}
@@ -3,26 +3,67 @@ package driller
 import (
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/drawing"
 	"github.com/landru29/cnc-drilling/internal/gcode"
 	"github.com/landru29/cnc-drilling/internal/geometry"
-	"github.com/yofu/dxf"
-	"github.com/yofu/dxf/entity"
 )
 
 // Process is the drilling process.
 func Process(in io.Reader, out io.Writer, config configuration.Config) error {
-	drawing, err := dxf.FromReader(in)
+	input, err := drawing.FromReader(in)
 	if err != nil {
 		return err
 	}
 
-	defer func(closer io.Closer) {
-		_ = closer.Close()
-	}(drawing)
+	setOfPoints := []geometry.Linker{}
+	holes := []geometry.Hole{}
 
-	if _, err := fmt.Fprintf(out, "G90\nG21\nG0 Z%.01f\n", config.SecurityZ); err != nil {
+	var shapeBox *geometry.Box
+
+	for _, element := range input.Filter(config.Layers...) {
+		switch element.Kind {
+		case drawing.KindPoint:
+			setOfPoints = append(setOfPoints, element.Linker)
+		case drawing.KindCircle:
+			center, radius, ok := element.Circle()
+			if !config.Circles || !ok {
+				continue
+			}
+
+			holes = append(holes, geometry.Hole{
+				Point:    geometry.Point{Name: fmt.Sprintf("#%d / Layer %s", len(holes), element.Layer), Coordinates: center},
+				Diameter: 2 * radius,
+			})
+		case drawing.KindLine:
+			if _, ok := input.Tools[element.Layer]; !ok {
+				continue
+			}
+		default:
+			continue
+		}
+
+		currentBox := element.Linker.Box()
+
+		if shapeBox == nil {
+			shapeBox = &currentBox
+
+			continue
+		}
+
+		currentBox = currentBox.Merge(*shapeBox)
+		shapeBox = &currentBox
+	}
+
+	for _, hole := range holes {
+		if hole.Undersized(config.ToolDiameter) {
+			return fmt.Errorf("hole %s D%.03f is smaller than the tool D%.03f", hole.Name, hole.Diameter, config.ToolDiameter)
+		}
+	}
+
+	if _, err := fmt.Fprint(out, config.Post.Preamble(config.SecurityZ)); err != nil {
 		return err
 	}
 
@@ -30,42 +71,231 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 		return err
 	}
 
-	setOfPoints := []*entity.Point{}
-	var shapeBox *geometry.Box
+	if _, err := fmt.Fprint(out, config.Spindle().Start()); err != nil {
+		return err
+	}
 
-	for _, geometryElement := range geometry.FilterEntities(drawing.Entities(), config.Layers...) {
-		if point, ok := geometryElement.(*entity.Point); ok {
-			setOfPoints = append(setOfPoints, point)
+	if len(input.Tools) > 0 {
+		if err := drillTools(input, out, config, shapeBox); err != nil {
+			return err
+		}
+	} else if err := drillPoints(geometry.PointsFromLinkers(setOfPoints...), out, config, shapeBox); err != nil {
+		return err
+	}
 
-			data := geometry.NewLinker("", geometryElement)
-			if data == nil {
-				continue
+	for _, holes := range geometry.GroupHoles(holes...) {
+		if _, err := fmt.Fprintf(out, ";\n;=== Holes D%.03f (%d) ===\n", holes[0].Diameter, len(holes)); err != nil {
+			return err
+		}
+
+		if !holes[0].Helical(config.ToolDiameter) {
+			holePoints := make([]geometry.Point, len(holes))
+			for idx, hole := range holes {
+				holePoints[idx] = hole.Point
 			}
 
-			currentBox := data.Box()
+			if err := drillPoints(holePoints, out, config, shapeBox); err != nil {
+				return err
+			}
 
-			if shapeBox == nil {
-				shapeBox = &currentBox
+			continue
+		}
 
-				continue
+		if config.Optimize {
+			holes = geometry.OptimizeTour(tourStart(config, shapeBox), holes, config.OptimizeTime)
+		}
+
+		for idx, hole := range holes {
+			code, err := gcode.Marshal(
+				hole,
+				gcode.WithDeep(config.Deepness),
+				gcode.WithFeed(config.Feed),
+				gcode.WithSecurityZ(config.SecurityZ),
+				gcode.WithPlungeFeed(config.PlungeFeed),
+				gcode.WithRetractFeed(config.RetractFeed),
+				gcode.WithClearance(config.Clearance),
+				gcode.WithOffset(config.Origin.Computed(shapeBox)),
+				gcode.WithToolDiameter(config.ToolDiameter),
+				gcode.WithPeck(config.DeepPerTry),
+			)
+			if err != nil {
+				return err
 			}
 
-			currentBox = currentBox.Merge(*shapeBox)
-			shapeBox = &currentBox
+			if _, err := fmt.Fprintf(out, ";\n;=== Helical #%d ===\n%s", idx, string(code)); err != nil {
+				return err
+			}
 		}
 	}
 
+	if _, err := fmt.Fprint(out, config.Spindle().Stop()); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "%s\n", config.AfterScript); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// drillCycle drills each point to the full deep before moving to the next one.
+// The R plane is the security Z, and the peck increment is the deep per try.
+func drillCycle(points []geometry.Point, out io.Writer, config configuration.Config, shapeBox *geometry.Box) error {
+	if config.Cycle.Canned() {
+		if _, err := fmt.Fprintf(out, "G98\n"); err != nil {
+			return err
+		}
+	}
+
+	for idx, point := range points {
+		code, err := gcode.Marshal(
+			point,
+			gcode.WithDeep(config.Deepness),
+			gcode.WithFeed(config.Feed),
+			gcode.WithSecurityZ(config.SecurityZ),
+			gcode.WithPlungeFeed(config.PlungeFeed),
+			gcode.WithRetractFeed(config.RetractFeed),
+			gcode.WithClearance(config.Clearance),
+			gcode.WithOffset(config.Origin.Computed(shapeBox)),
+			gcode.WithCycle(config.Cycle),
+			gcode.WithPeck(config.DeepPerTry),
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(
+			out,
+			";\n;=== Drilling #%d ===\n%s",
+			idx,
+			string(code),
+		); err != nil {
+			return err
+		}
+	}
+
+	if config.Cycle.Canned() {
+		if _, err := fmt.Fprintf(out, "G80\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tourStart is the position of the tool before drilling, in the coordinates of the drawing: the
+// origin of the program.
+func tourStart(config configuration.Config, shapeBox *geometry.Box) geometry.Coordinates {
+	origin := config.Origin.Computed(shapeBox)
+
+	return geometry.Coordinates{X: origin[0], Y: origin[1]}
+}
+
+// drillPoints drills the points, with the configured cycle or try after try.
+func drillPoints(points []geometry.Point, out io.Writer, config configuration.Config, shapeBox *geometry.Box) error {
+	if config.Optimize {
+		points = geometry.OptimizeTour(tourStart(config, shapeBox), points, config.OptimizeTime)
+	}
+
+	if config.Cycle != gcode.CycleNone {
+		return drillCycle(points, out, config, shapeBox)
+	}
+
 	tryDeeps := config.TryDeeps()
 
-	for deepIndex, deep := range tryDeeps {
+	for _, pass := range config.Passes(len(points)) {
+		code, err := gcode.Marshal(
+			points[pass.Element],
+			gcode.WithDeep(tryDeeps[pass.Try]),
+			gcode.WithFeed(config.Feed),
+			gcode.WithSecurityZ(config.SecurityZ),
+			gcode.WithPlungeFeed(config.PlungeFeed),
+			gcode.WithRetractFeed(config.RetractFeed),
+			gcode.WithClearance(config.Clearance),
+			gcode.WithOffset(config.Origin.Computed(shapeBox)),
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(
+			out,
+			";\n;=== Drilling #%d %d/%d ===\n%s",
+			pass.Element,
+			pass.Try+1,
+			len(tryDeeps),
+			string(code),
+		); err != nil {
+			return err
+		}
+	}
 
-		for idx, point := range geometry.PointsFromDXFPoints(geometry.WithDXFPoints(setOfPoints...)) {
+	return nil
+}
 
+// drillTools drills the holes and the slots of a drill file tool after tool.
+// The machine pauses between the tools to let the operator change it, the first tool being loaded
+// before the program starts.
+func drillTools(input *drawing.Drawing, out io.Writer, config configuration.Config, shapeBox *geometry.Box) error {
+	changeTool := false
+
+	for _, layer := range input.Layers {
+		if len(config.Layers) > 0 && !slices.Contains(config.Layers, layer) {
+			continue
+		}
+
+		points := []geometry.Linker{}
+		slots := []geometry.Path{}
+
+		for _, element := range input.Filter(layer) {
+			switch element.Kind {
+			case drawing.KindPoint:
+				points = append(points, element.Linker)
+			case drawing.KindLine:
+				slots = append(slots, geometry.Path{element.Linker})
+			}
+		}
+
+		if len(points)+len(slots) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(out, ";\n;=== Tool %s D%.03f ===\n", layer, input.Tools[layer]); err != nil {
+			return err
+		}
+
+		if changeTool {
+			if _, err := fmt.Fprintf(
+				out,
+				"G0 Z%.03f\n%sM0 ; Change tool: %s D%.03f\n%s",
+				config.SecurityZ,
+				config.Spindle().Stop(),
+				layer,
+				input.Tools[layer],
+				config.Spindle().Start(),
+			); err != nil {
+				return err
+			}
+		}
+
+		changeTool = true
+
+		if err := drillPoints(geometry.PointsFromLinkers(points...), out, config, shapeBox); err != nil {
+			return err
+		}
+
+		tryDeeps := config.TryDeeps()
+
+		for _, pass := range config.Passes(len(slots)) {
 			code, err := gcode.Marshal(
-				point,
-				gcode.WithDeep(deep),
+				slots[pass.Element],
+				gcode.WithDeep(tryDeeps[pass.Try]),
 				gcode.WithFeed(config.Feed),
 				gcode.WithSecurityZ(config.SecurityZ),
+				gcode.WithPlungeFeed(config.PlungeFeed),
+				gcode.WithRetractFeed(config.RetractFeed),
+				gcode.WithClearance(config.Clearance),
 				gcode.WithOffset(config.Origin.Computed(shapeBox)),
 			)
 			if err != nil {
@@ -74,9 +304,9 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 
 			if _, err := fmt.Fprintf(
 				out,
-				";\n;=== Drilling #%d %d/%d ===\n%s",
-				idx,
-				deepIndex+1,
+				";\n;=== Slot #%d %d/%d ===\n%s",
+				pass.Element,
+				pass.Try+1,
 				len(tryDeeps),
 				string(code),
 			); err != nil {
@@ -85,9 +315,5 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 		}
 	}
 
-	if _, err := fmt.Fprintf(out, "%s\n", config.AfterScript); err != nil {
-		return err
-	}
-
 	return nil
 }
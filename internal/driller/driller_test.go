@@ -0,0 +1,210 @@
+package driller_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/driller"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yofu/dxf"
+)
+
+func TestProcessCircles(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	for _, circle := range [][3]float64{{10, 10, 1.5}, {20, 10, 4}, {30, 10, 1.5}} {
+		_, err := drawing.Circle(circle[0], circle[1], 0, circle[2])
+		require.NoError(t, err)
+	}
+
+	source := bytes.NewBuffer(nil)
+	_, err := drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, driller.Process(
+		bytes.NewReader(source.Bytes()),
+		out,
+		configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 3, DeepPerTry: 1, ToolDiameter: 3, Circles: true},
+	))
+
+	output := out.String()
+
+	assert.Contains(t, output, ";=== Holes D3.000 (2) ===")
+	assert.Contains(t, output, ";=== Holes D8.000 (1) ===")
+	assert.Less(t, strings.Index(output, "D3.000"), strings.Index(output, "D8.000"))
+
+	assert.Contains(t, output, "G0 X10.000 Y10.000\nG1 Z-3.000")
+	assert.Contains(t, output, "G0 X22.500 Y10.000\nG1 Z0.000 F100.000\n")
+	assert.Contains(t, output, "G3 X22.500 Y10.000 Z-1.000 I-2.500 J0.000 F100.000\n")
+	assert.Contains(t, output, "G3 X22.500 Y10.000 Z-3.000 I-2.500 J0.000 F100.000\n")
+	assert.Equal(t, 4, strings.Count(output, "G3 "))
+}
+
+func TestProcessOrder(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	for _, point := range [][2]float64{{10, 10}, {20, 10}} {
+		_, err := drawing.Point(point[0], point[1], 0)
+		require.NoError(t, err)
+	}
+
+	source := bytes.NewBuffer(nil)
+	_, err := drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	for name, testCase := range map[string]struct {
+		order    configuration.Order
+		expected []string
+	}{
+		"level": {
+			order:    configuration.OrderLevel,
+			expected: []string{"#0 1/2", "#1 1/2", "#0 2/2", "#1 2/2"},
+		},
+		"depth": {
+			order:    configuration.OrderDepth,
+			expected: []string{"#0 1/2", "#0 2/2", "#1 1/2", "#1 2/2"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+
+			require.NoError(t, driller.Process(
+				bytes.NewReader(source.Bytes()),
+				out,
+				configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 2, DeepPerTry: 1, Order: testCase.order},
+			))
+
+			passes := []string{}
+
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, ";=== Drilling ") {
+					passes = append(passes, strings.TrimSuffix(strings.TrimPrefix(line, ";=== Drilling "), " ==="))
+				}
+			}
+
+			assert.Equal(t, testCase.expected, passes)
+		})
+	}
+}
+
+func TestProcessCycles(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	for _, point := range [][2]float64{{10, 10}, {20, 10}} {
+		_, err := drawing.Point(point[0], point[1], 0)
+		require.NoError(t, err)
+	}
+
+	source := bytes.NewBuffer(nil)
+	_, err := drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	for name, testCase := range map[string]struct {
+		cycle    gcode.Cycle
+		expected []string
+	}{
+		"g81": {
+			cycle:    gcode.CycleG81,
+			expected: []string{"G98\n;\n;=== Drilling #0 ===", "G81 X10.000 Y10.000 Z-3.000 R5.000 F100.000\n", "G81 X20.000 Y10.000 Z-3.000 R5.000 F100.000\nG80\n"},
+		},
+		"g83": {
+			cycle:    gcode.CycleG83,
+			expected: []string{"G98\n;\n;=== Drilling #0 ===", "G83 X10.000 Y10.000 Z-3.000 R5.000 Q1.000 F100.000\n", "G83 X20.000 Y10.000 Z-3.000 R5.000 Q1.000 F100.000\nG80\n"},
+		},
+		"g73": {
+			cycle:    gcode.CycleG73,
+			expected: []string{"G98\n;\n;=== Drilling #0 ===", "G73 X10.000 Y10.000 Z-3.000 R5.000 Q1.000 F100.000\n", "G73 X20.000 Y10.000 Z-3.000 R5.000 Q1.000 F100.000\nG80\n"},
+		},
+		"expanded": {
+			cycle:    gcode.CycleExpanded,
+			expected: []string{"G0 X20.000 Y10.000\nG1 Z-1.000 F100.000; Peck\nG0 Z5.000; Tool up\nG0 Z-0.500\nG1 Z-2.000 F100.000; Peck\n"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+
+			require.NoError(t, driller.Process(
+				bytes.NewReader(source.Bytes()),
+				out,
+				configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 3, DeepPerTry: 1, Cycle: testCase.cycle},
+			))
+
+			for _, expected := range testCase.expected {
+				assert.Contains(t, out.String(), expected)
+			}
+
+			assert.Equal(t, testCase.cycle.Canned(), strings.Contains(out.String(), "G98\n"))
+		})
+	}
+}
+
+func TestProcessOptimizeFromOrigin(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	for _, x := range []float64{20, 40, 10, 30} {
+		_, err := drawing.Point(x, 10, 0)
+		require.NoError(t, err)
+	}
+
+	source := bytes.NewBuffer(nil)
+	_, err := drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	config := configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 3, Optimize: true, OptimizeTime: time.Second}
+	require.NoError(t, config.Origin.Set("40,10"))
+
+	require.NoError(t, driller.Process(bytes.NewReader(source.Bytes()), out, config))
+
+	// The tool starts at the origin, on the last point.
+	output := out.String()
+	assert.Less(t, strings.Index(output, "G0 X0.000 Y0.000"), strings.Index(output, "G0 X-10.000 Y0.000"))
+	assert.Less(t, strings.Index(output, "G0 X-20.000 Y0.000"), strings.Index(output, "G0 X-30.000 Y0.000"))
+}
+
+func TestProcessUndersizedHole(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	_, err := drawing.Circle(10, 10, 0, 1)
+	require.NoError(t, err)
+
+	source := bytes.NewBuffer(nil)
+	_, err = drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	err = driller.Process(
+		bytes.NewReader(source.Bytes()),
+		out,
+		configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 3, ToolDiameter: 3, Circles: true, SpindleSpeed: 10000},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "D2.000 is smaller than the tool D3.000")
+	assert.Empty(t, out.String())
+}
+
+func TestProcessToolChange(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, driller.Process(
+		strings.NewReader("M48\nMETRIC,TZ\nT1C0.8\nT2C1.0\n%\nT1\nX10.0Y10.0\nT2\nX20.0Y10.0\nM30\n"),
+		out,
+		configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 3, SpindleSpeed: 10000},
+	))
+
+	output := out.String()
+
+	assert.Equal(t, 1, strings.Count(output, "M0 "))
+	assert.Equal(t, 2, strings.Count(output, "M3 S10000"))
+	assert.Less(t, strings.Index(output, ";=== Tool T2 D1.000 ==="), strings.Index(output, "M0 ; Change tool: T2 D1.000"))
+	assert.Contains(t, output, ";=== Tool T1 D0.800 ===\n;\n;=== Drilling #0 1/1 ===\n")
+}
@@ -3,68 +3,47 @@ package engraver
 import (
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/drawing"
+	"github.com/landru29/cnc-drilling/internal/font"
 	"github.com/landru29/cnc-drilling/internal/gcode"
 	"github.com/landru29/cnc-drilling/internal/geometry"
-	"github.com/yofu/dxf"
-	"github.com/yofu/dxf/entity"
 )
 
+// tolerance is the chord tolerance used to approximate curves.
+const tolerance = 0.01
+
 // Process is the engraving process.
 func Process(in io.Reader, out io.Writer, config configuration.Config) error {
-	drawing, err := dxf.FromReader(in)
+	input, err := drawing.FromReader(in)
 	if err != nil {
 		return err
 	}
 
-	defer func(closer io.Closer) {
-		_ = closer.Close()
-	}(drawing)
-
-	if _, err := fmt.Fprintf(out, "G90\nG21\nG0 Z%.01f\n", config.SecurityZ); err != nil {
-		return err
-	}
-
-	if _, err := fmt.Fprintf(out, "%s\n", config.BeforeScript); err != nil {
-		return err
-	}
-
-	arcs := []*entity.Arc{}
-	lines := []*entity.Line{}
-	lightPolylines := []*entity.LwPolyline{}
-	polylines := []*entity.Polyline{}
-	circles := []*entity.Circle{}
+	linkers := []geometry.Linker{}
 
 	var shapeBox *geometry.Box
 
-	for _, geometryElement := range geometry.FilterEntities(drawing.Entities(), config.Layers...) {
-		if arc, ok := geometryElement.(*entity.Arc); ok {
-			arcs = append(arcs, arc)
-		}
-
-		if line, ok := geometryElement.(*entity.Line); ok {
-			lines = append(lines, line)
-		}
+	tabPoints := []geometry.Coordinates{}
 
-		if lightPolyline, ok := geometryElement.(*entity.LwPolyline); ok {
-			lightPolylines = append(lightPolylines, lightPolyline)
-		}
-
-		if polyline, ok := geometryElement.(*entity.Polyline); ok {
-			polylines = append(polylines, polyline)
-		}
+	for _, element := range input.Filter(config.Layers...) {
+		if config.TabLayer != "" && element.Layer == config.TabLayer {
+			if element.Kind == drawing.KindPoint {
+				tabPoints = append(tabPoints, *element.Linker.Start())
+			}
 
-		if circle, ok := geometryElement.(*entity.Circle); ok {
-			circles = append(circles, circle)
+			continue
 		}
 
-		data := geometry.NewLinker("", geometryElement)
-		if data == nil {
-			continue
+		switch element.Kind {
+		case drawing.KindLine, drawing.KindArc, drawing.KindCircle, drawing.KindPolyline, drawing.KindLightPolyline, drawing.KindPath,
+			drawing.KindEllipse, drawing.KindSpline, drawing.KindText:
+			linkers = append(linkers, element.Linker)
 		}
 
-		currentBox := data.Box()
+		currentBox := element.Linker.Box()
 
 		if shapeBox == nil {
 			shapeBox = &currentBox
@@ -76,44 +55,266 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 		shapeBox = &currentBox
 	}
 
+	return engrave(out, geometry.PathsFromLinkers(linkers...), tabPoints, shapeBox, config)
+}
+
+// ProcessText is the text engraving process. The text is drawn with the single-stroke font.
+func ProcessText(text string, style font.Style, out io.Writer, config configuration.Config) error {
+	paths := font.Render("Text", text, style)
+
+	var shapeBox *geometry.Box
+
+	for _, path := range paths {
+		currentBox := path.Box()
+
+		if shapeBox != nil {
+			currentBox = currentBox.Merge(*shapeBox)
+		}
+
+		shapeBox = &currentBox
+	}
+
+	return engrave(out, paths, nil, shapeBox, config)
+}
+
+// engrave writes the gcode of the paths, try after try.
+func engrave(
+	out io.Writer,
+	paths []geometry.Path,
+	tabPoints []geometry.Coordinates,
+	shapeBox *geometry.Box,
+	config configuration.Config,
+) error {
+	if _, err := fmt.Fprint(out, config.Post.Preamble(config.SecurityZ)); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "%s\n", config.BeforeScript); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(out, config.Spindle().Start()); err != nil {
+		return err
+	}
+
+	if config.InnerFirst {
+		paths = geometry.InnerFirst(paths, tolerance)
+	}
+
+	paths, err := compensate(paths, config)
+	if err != nil {
+		return err
+	}
+
+	paths = addTabs(orient(paths, config), tabPoints, config)
+	cuts := addLeads(paths, config)
+
 	tryDeeps := config.TryDeeps()
 
-	for deepIndex, deep := range tryDeeps {
+	if config.Spiral {
+		for idx, path := range paths {
+			if !path.IsClosed() {
+				continue
+			}
 
-		for idx, path := range geometry.PathsFromDXF(
-			geometry.WithDXFLines(lines...),
-			geometry.WithDXFArcs(arcs...),
-			geometry.WithDXFLwPolyline(lightPolylines...),
-			geometry.WithDXFPolyline(polylines...),
-			geometry.WithDXFCircle(circles...),
-		) {
 			code, err := gcode.Marshal(
-				path,
-				gcode.WithDeep(deep),
+				cuts[idx],
+				gcode.WithDeep(config.Deepness),
 				gcode.WithFeed(config.Feed),
 				gcode.WithSecurityZ(config.SecurityZ),
+				gcode.WithPlungeFeed(config.PlungeFeed),
+				gcode.WithRetractFeed(config.RetractFeed),
+				gcode.WithClearance(config.Clearance),
 				gcode.WithOffset(config.Origin.Computed(shapeBox)),
+				gcode.WithPreviousDeep(config.DeepStart),
+				gcode.WithSpiral(tryDeeps),
 			)
 			if err != nil {
 				return err
 			}
 
-			if _, err := fmt.Fprintf(
-				out,
-				";\n;=== Path #%d %d/%d ===\n%s",
-				idx,
-				deepIndex+1,
-				len(tryDeeps),
-				string(code),
-			); err != nil {
+			if _, err := fmt.Fprintf(out, ";\n;=== Path #%d spiral ===\n%s", idx, string(code)); err != nil {
 				return err
 			}
 		}
 	}
 
+	for _, pass := range config.Passes(len(paths)) {
+		if config.Spiral && paths[pass.Element].IsClosed() {
+			continue
+		}
+
+		code, err := gcode.Marshal(
+			cuts[pass.Element],
+			gcode.WithDeep(tryDeeps[pass.Try]),
+			gcode.WithFeed(config.Feed),
+			gcode.WithSecurityZ(config.SecurityZ),
+			gcode.WithPlungeFeed(config.PlungeFeed),
+			gcode.WithRetractFeed(config.RetractFeed),
+			gcode.WithClearance(config.Clearance),
+			gcode.WithOffset(config.Origin.Computed(shapeBox)),
+			gcode.WithEntry(config.Entry, config.RampAngle),
+			gcode.WithPreviousDeep(config.PreviousDeep(pass.Try)),
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(
+			out,
+			";\n;=== Path #%d %d/%d ===\n%s",
+			pass.Element,
+			pass.Try+1,
+			len(tryDeeps),
+			string(code),
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprint(out, config.Spindle().Stop()); err != nil {
+		return err
+	}
+
 	if _, err := fmt.Fprintf(out, "%s\n", config.AfterScript); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// compensate offsets the closed paths by the tool radius. Open paths are engraved on-line.
+func compensate(paths []geometry.Path, config configuration.Config) ([]geometry.Path, error) {
+	if config.Side == geometry.SideOn {
+		return paths, nil
+	}
+
+	if config.ToolDiameter <= 0 {
+		return nil, fmt.Errorf("tool diameter is required to engrave %s", config.Side)
+	}
+
+	output := make([]geometry.Path, len(paths))
+
+	for idx, path := range paths {
+		if !path.IsClosed() {
+			output[idx] = path
+
+			continue
+		}
+
+		compensated, err := path.Compensate(config.Side, config.ToolDiameter/2)
+		if err != nil {
+			return nil, fmt.Errorf("path #%d: %w", idx, err)
+		}
+
+		output[idx] = compensated
+	}
+
+	return output, nil
+}
+
+// orient sets the direction of the closed paths for climb or conventional milling. Without compensation,
+// the outermost paths are outside profiles, the paths nested in them are inside profiles, and so on.
+func orient(paths []geometry.Path, config configuration.Config) []geometry.Path {
+	if config.Milling == geometry.MillingAny {
+		return paths
+	}
+
+	levels := map[int]int{}
+
+	for _, contour := range geometry.ContainmentTree(paths, tolerance) {
+		contour.Walk(func(current *geometry.Contour, level int) {
+			levels[current.Index] = level
+		})
+	}
+
+	output := make([]geometry.Path, len(paths))
+
+	for idx, path := range paths {
+		outside := levels[idx]%2 == 0
+
+		switch config.Side {
+		case geometry.SideOutside:
+			outside = true
+		case geometry.SideInside:
+			outside = false
+		}
+
+		output[idx] = path.Wound(config.Milling.Clockwise(outside, config.SpindleDirection))
+	}
+
+	return output
+}
+
+// addLeads joins the closed paths with leads on the waste side when the tool is compensated.
+func addLeads(paths []geometry.Path, config configuration.Config) []geometry.Path {
+	if config.Side == geometry.SideOn || config.Lead == geometry.LeadNone {
+		return paths
+	}
+
+	output := make([]geometry.Path, len(paths))
+
+	for idx, path := range paths {
+		output[idx] = path.WithLeads(config.Lead, config.LeadLength, config.Overlap, config.Side == geometry.SideOutside)
+	}
+
+	return output
+}
+
+// addTabs lifts the tool over the tabs of the closed paths. Tabs are spread evenly (by count
+// or spacing) and placed on the nearest closed path of each tab point.
+func addTabs(paths []geometry.Path, tabPoints []geometry.Coordinates, config configuration.Config) []geometry.Path {
+	if config.TabHeight <= 0 {
+		return paths
+	}
+
+	positions := make([][]float64, len(paths))
+
+	for idx, path := range paths {
+		if !path.IsClosed() {
+			continue
+		}
+
+		count := config.TabCount
+		if config.TabSpacing > 0 {
+			count = max(1, int(math.Floor(path.Length()/config.TabSpacing)))
+		}
+
+		positions[idx] = path.TabPositions(count)
+	}
+
+	for _, point := range tabPoints {
+		nearest := -1
+		nearestPosition := 0.0
+		nearestDistance := math.Inf(1)
+
+		for idx, path := range paths {
+			if !path.IsClosed() {
+				continue
+			}
+
+			position, distance := path.Project(point)
+			if distance < nearestDistance {
+				nearest = idx
+				nearestPosition = position
+				nearestDistance = distance
+			}
+		}
+
+		if nearest >= 0 {
+			positions[nearest] = append(positions[nearest], nearestPosition)
+		}
+	}
+
+	output := make([]geometry.Path, len(paths))
+
+	for idx, path := range paths {
+		output[idx] = path.WithTabs(
+			positions[idx],
+			config.TabWidth+config.ToolDiameter,
+			config.Deepness-config.TabHeight,
+		)
+	}
+
+	return output
+}
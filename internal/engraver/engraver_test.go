@@ -0,0 +1,108 @@
+package engraver_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/engraver"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yofu/dxf"
+)
+
+func TestProcessBaloon(t *testing.T) {
+	source, err := os.ReadFile("../../testdata/baloon.dxf")
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, engraver.Process(
+		bytes.NewReader(source),
+		out,
+		configuration.Config{Feed: 60, SecurityZ: 5, Deepness: 1},
+	))
+
+	assert.Contains(t, out.String(), ";=== Path #0 1/1 ===\n")
+	assert.Positive(t, strings.Count(out.String(), "G2 "))
+}
+
+func TestProcessSpiralLeads(t *testing.T) {
+	source, err := os.ReadFile("../../testdata/rectangle.dxf")
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, engraver.Process(
+		bytes.NewReader(source),
+		out,
+		configuration.Config{
+			Feed:         60,
+			SecurityZ:    5,
+			Deepness:     2,
+			DeepPerTry:   1,
+			ToolDiameter: 2,
+			Side:         geometry.SideOutside,
+			Spiral:       true,
+			Lead:         geometry.LeadLine,
+			LeadLength:   2,
+			Overlap:      3,
+		},
+	))
+
+	output := out.String()
+
+	assert.Contains(t, output, ";=== Path #0 spiral ===\nG0 X30.000 Y17.000\nG1 Z0.000 F60.000; Tool down\n;------ Segment #0 / Layer 0 (lead-in)\nG1 X30.000 Y19.000 F60.000\n")
+	assert.Contains(t, output, "G1 X30.000 Y19.000 Z-2.000 F60.000\n")
+	assert.Contains(t, output, "G2 X27.037 Y19.407 I0.000 J11.000 F60.000\n;------ Segment #0 / Layer 0 (lead-out)\nG1 X26.498 Y17.480 F60.000\nG0 Z5.000; Tool up\n")
+}
+
+func TestProcessGolden(t *testing.T) {
+	source, err := os.ReadFile("../../testdata/point01.dxf")
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("../../testdata/point01.gcode")
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, engraver.Process(
+		bytes.NewReader(source),
+		out,
+		configuration.Config{Feed: 60, SecurityZ: 5, Deepness: 1, AfterScript: "G0X0Y0", TabWidth: 5, RampAngle: 3, LeadLength: 2},
+	))
+
+	assert.Equal(t, string(expected), out.String())
+}
+
+func TestProcessInnerFirst(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	for _, square := range [][2]float64{{0, 100}, {40, 60}} {
+		low, high := square[0], square[1]
+
+		for _, side := range [][4]float64{{low, low, high, low}, {high, low, high, high}, {high, high, low, high}, {low, high, low, low}} {
+			_, err := drawing.Line(side[0], side[1], 0, side[2], side[3], 0)
+			require.NoError(t, err)
+		}
+	}
+
+	source := bytes.NewBuffer(nil)
+	_, err := drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	for innerFirst, start := range map[bool]string{false: "G0 X0.000 Y0.000\n", true: "G0 X60.000 Y60.000\n"} {
+		out := bytes.NewBuffer(nil)
+
+		require.NoError(t, engraver.Process(
+			bytes.NewReader(source.Bytes()),
+			out,
+			configuration.Config{Feed: 60, SecurityZ: 5, Deepness: 1, InnerFirst: innerFirst},
+		))
+
+		assert.Contains(t, out.String(), ";=== Path #0 1/1 ===\n"+start)
+	}
+}
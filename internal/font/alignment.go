@@ -0,0 +1,128 @@
+package font
+
+import "fmt"
+
+// Alignment is the horizontal alignment of a text on its position.
+type Alignment int
+
+const (
+	// AlignLeft starts the text at its position.
+	AlignLeft Alignment = iota
+
+	// AlignCenter centers the text on its position.
+	AlignCenter
+
+	// AlignRight ends the text at its position.
+	AlignRight
+)
+
+// String implements the pflag.Value interface.
+func (a Alignment) String() string {
+	switch a {
+	case AlignLeft:
+		return "left"
+	case AlignCenter:
+		return "center"
+	case AlignRight:
+		return "right"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (a *Alignment) Set(value string) error {
+	switch value {
+	case "left", "":
+		*a = AlignLeft
+	case "center":
+		*a = AlignCenter
+	case "right":
+		*a = AlignRight
+	default:
+		return fmt.Errorf("unknown alignment: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (a Alignment) Type() string {
+	return "alignment"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (a Alignment) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (a *Alignment) UnmarshalText(data []byte) error {
+	return a.Set(string(data))
+}
+
+// VerticalAlignment is the vertical alignment of a text on its position.
+type VerticalAlignment int
+
+const (
+	// VerticalBaseline puts the baseline of the first line on the position.
+	VerticalBaseline VerticalAlignment = iota
+
+	// VerticalBottom puts the bottom of the descenders of the last line on the position.
+	VerticalBottom
+
+	// VerticalMiddle centers the text on the position.
+	VerticalMiddle
+
+	// VerticalTop puts the top of the capital letters of the first line on the position.
+	VerticalTop
+)
+
+// String implements the pflag.Value interface.
+func (v VerticalAlignment) String() string {
+	switch v {
+	case VerticalBaseline:
+		return "baseline"
+	case VerticalBottom:
+		return "bottom"
+	case VerticalMiddle:
+		return "middle"
+	case VerticalTop:
+		return "top"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (v *VerticalAlignment) Set(value string) error {
+	switch value {
+	case "baseline", "":
+		*v = VerticalBaseline
+	case "bottom":
+		*v = VerticalBottom
+	case "middle":
+		*v = VerticalMiddle
+	case "top":
+		*v = VerticalTop
+	default:
+		return fmt.Errorf("unknown vertical alignment: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (v VerticalAlignment) Type() string {
+	return "vertical-alignment"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v VerticalAlignment) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (v *VerticalAlignment) UnmarshalText(data []byte) error {
+	return v.Set(string(data))
+}
@@ -0,0 +1,159 @@
+package font
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+const (
+	// capHeight is the height of the capital letters, in font units.
+	capHeight = 21
+
+	// descent is the depth of the descenders under the baseline, in font units.
+	descent = 7
+
+	// lineSpacing is the distance between two baselines, relative to the text height.
+	lineSpacing = 5.0 / 3
+)
+
+// Style is the way a text is drawn. The height is the one of the capital letters, the spacing is
+// added between letters (both in millimeters), and the rotation is counterclockwise, in degrees.
+type Style struct {
+	Height   float64
+	Spacing  float64
+	Rotation float64
+	Align    Alignment
+	Vertical VerticalAlignment
+	Position geometry.Coordinates
+}
+
+// Render draws a text with the single-stroke font. Each stroke is a path. Lines are separated by
+// line feeds, and the characters missing in the font are left blank.
+func Render(name string, text string, style Style) []geometry.Path {
+	scale := style.Height / capHeight
+	lines := strings.Split(strings.ReplaceAll(text, "\t", " "), "\n")
+	advance := style.Height * lineSpacing
+
+	var top float64
+
+	switch style.Vertical {
+	case VerticalBaseline:
+		top = 0
+	case VerticalBottom:
+		top = float64(len(lines)-1)*advance + descent*scale
+	case VerticalMiddle:
+		top = (float64(len(lines)-1)*advance - style.Height) / 2
+	case VerticalTop:
+		top = -style.Height
+	}
+
+	angle := style.Rotation * math.Pi / 180
+	cos, sin := math.Cos(angle), math.Sin(angle)
+
+	place := func(x float64, y float64) geometry.Coordinates {
+		return geometry.Coordinates{
+			X: style.Position.X + cos*x - sin*y,
+			Y: style.Position.Y + sin*x + cos*y,
+		}
+	}
+
+	output := []geometry.Path{}
+
+	for lineIndex, line := range lines {
+		left := 0.0
+
+		switch width := Width(line, style); style.Align {
+		case AlignCenter:
+			left = -width / 2
+		case AlignRight:
+			left = -width
+		}
+
+		baseline := top - float64(lineIndex)*advance
+
+		for _, character := range line {
+			glyph := glyphOf(character)
+
+			for _, stroke := range glyph.strokes() {
+				path := geometry.Path{}
+
+				for idx := 1; idx < len(stroke); idx++ {
+					from := place(left+float64(stroke[idx-1][0])*scale, baseline+float64(stroke[idx-1][1])*scale)
+					to := place(left+float64(stroke[idx][0])*scale, baseline+float64(stroke[idx][1])*scale)
+
+					if from.Equal(to) {
+						continue
+					}
+
+					path = append(path, &geometry.Segment{
+						Name:       fmt.Sprintf("%s / %q", name, character),
+						StartPoint: from,
+						EndPoint:   to,
+					})
+				}
+
+				if len(path) > 0 {
+					output = append(output, path)
+				}
+			}
+
+			left += float64(glyph.width())*scale + style.Spacing
+		}
+	}
+
+	return output
+}
+
+// Width is the length of a line of text, in millimeters.
+func Width(line string, style Style) float64 {
+	characters := []rune(line)
+	if len(characters) == 0 {
+		return 0
+	}
+
+	output := style.Spacing * float64(len(characters)-1)
+
+	for _, character := range characters {
+		output += float64(glyphOf(character).width()) * style.Height / capHeight
+	}
+
+	return output
+}
+
+// glyph is the definition of a character in the font.
+type glyph []int
+
+// glyphOf gives the glyph of a character. Missing characters are drawn as spaces.
+func glyphOf(character rune) glyph {
+	if character < ' ' || int(character-' ') >= len(simplex) {
+		return simplex[0]
+	}
+
+	return simplex[character-' ']
+}
+
+func (g glyph) width() int {
+	return g[1]
+}
+
+// strokes gives the lines of the glyph, as lists of points.
+func (g glyph) strokes() [][][2]int {
+	output := [][][2]int{}
+	stroke := [][2]int{}
+
+	for idx := 2; idx+1 < len(g); idx += 2 {
+		if g[idx] == -1 && g[idx+1] == -1 {
+			output = append(output, stroke)
+			stroke = [][2]int{}
+
+			continue
+		}
+
+		stroke = append(stroke, [2]int{g[idx], g[idx+1]})
+	}
+
+	return append(output, stroke)
+}
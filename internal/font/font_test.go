@@ -0,0 +1,66 @@
+package font_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/font"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	paths := font.Render("text", "H", font.Style{Height: 21})
+	require.Len(t, paths, 3)
+
+	assert.Equal(t, geometry.Coordinates{X: 4, Y: 21}, *paths[0].Start())
+	assert.Equal(t, geometry.Coordinates{X: 4, Y: 0}, *paths[0].End())
+	assert.Equal(t, geometry.Coordinates{X: 4, Y: 11}, *paths[2].Start())
+	assert.Equal(t, geometry.Coordinates{X: 18, Y: 11}, *paths[2].End())
+}
+
+func TestRenderStyle(t *testing.T) {
+	style := font.Style{
+		Height:   10,
+		Spacing:  1,
+		Rotation: 90,
+		Align:    font.AlignCenter,
+		Vertical: font.VerticalTop,
+		Position: geometry.Coordinates{X: 100, Y: 50},
+	}
+
+	width := font.Width("II", style)
+	assert.InDelta(t, 2*8*10.0/21+1, width, 1e-9)
+
+	paths := font.Render("text", "II", style)
+	require.Len(t, paths, 2)
+
+	// Rotated by a quarter turn, the text goes up, and hangs on the right of the position.
+	box := paths[0].Box().Merge(paths[1].Box())
+	assert.InDelta(t, 100, box.Min.X, 1e-9)
+	assert.InDelta(t, 110, box.Max.X, 1e-9)
+	assert.InDelta(t, 50, (box.Min.Y+box.Max.Y)/2, 1e-9)
+}
+
+func TestRenderLines(t *testing.T) {
+	paths := font.Render("text", "I\néI", font.Style{Height: 3})
+	require.Len(t, paths, 2)
+
+	// The second line is 5/3 of the height below, and starts with a blank for the missing character.
+	assert.InDelta(t, -5, paths[1].End().Y, 1e-9)
+	assert.InDelta(t, 20*3.0/21, paths[1].End().X, 1e-9)
+}
+
+func TestAlignmentSet(t *testing.T) {
+	var alignment font.Alignment
+
+	require.NoError(t, alignment.Set("right"))
+	assert.Equal(t, font.AlignRight, alignment)
+	assert.Error(t, alignment.Set("justified"))
+
+	var vertical font.VerticalAlignment
+
+	require.NoError(t, vertical.Set("middle"))
+	assert.Equal(t, font.VerticalMiddle, vertical)
+	assert.Error(t, vertical.Set("center"))
+}
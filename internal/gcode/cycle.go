@@ -0,0 +1,81 @@
+package gcode
+
+import "fmt"
+
+// Cycle is the drilling cycle.
+type Cycle int
+
+const (
+	// CycleNone plunges straight to the requested deep.
+	CycleNone Cycle = iota
+
+	// CycleG81 is the simple drilling canned cycle.
+	CycleG81
+
+	// CycleG83 is the peck drilling canned cycle, retracting to the R plane after each peck.
+	CycleG83
+
+	// CycleG73 is the chip breaking canned cycle, retracting slightly after each peck.
+	CycleG73
+
+	// CycleExpanded pecks with plain moves, for controllers without canned cycles.
+	CycleExpanded
+)
+
+// String implements the pflag.Value interface.
+func (c Cycle) String() string {
+	switch c {
+	case CycleNone:
+		return "none"
+	case CycleG81:
+		return "g81"
+	case CycleG83:
+		return "g83"
+	case CycleG73:
+		return "g73"
+	case CycleExpanded:
+		return "expanded"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (c *Cycle) Set(value string) error {
+	switch value {
+	case "none", "":
+		*c = CycleNone
+	case "g81":
+		*c = CycleG81
+	case "g83":
+		*c = CycleG83
+	case "g73":
+		*c = CycleG73
+	case "expanded":
+		*c = CycleExpanded
+	default:
+		return fmt.Errorf("unknown drilling cycle: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (c Cycle) Type() string {
+	return "cycle"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c Cycle) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *Cycle) UnmarshalText(data []byte) error {
+	return c.Set(string(data))
+}
+
+// Canned tells whether the cycle is a canned cycle of the controller.
+func (c Cycle) Canned() bool {
+	return c == CycleG81 || c == CycleG83 || c == CycleG73
+}
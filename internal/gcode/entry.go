@@ -0,0 +1,62 @@
+package gcode
+
+import "fmt"
+
+// Entry is the way the tool goes down into the material at the start of a path.
+type Entry int
+
+const (
+	// EntryPlunge goes straight down.
+	EntryPlunge Entry = iota
+
+	// EntryRamp goes down back and forth along the first elements of the path.
+	EntryRamp
+
+	// EntryHelix goes down around closed paths. Open paths are ramped.
+	EntryHelix
+)
+
+// String implements the pflag.Value interface.
+func (e Entry) String() string {
+	switch e {
+	case EntryPlunge:
+		return "plunge"
+	case EntryRamp:
+		return "ramp"
+	case EntryHelix:
+		return "helix"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (e *Entry) Set(value string) error {
+	switch value {
+	case "plunge", "":
+		*e = EntryPlunge
+	case "ramp":
+		*e = EntryRamp
+	case "helix":
+		*e = EntryHelix
+	default:
+		return fmt.Errorf("unknown entry: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (e Entry) Type() string {
+	return "entry"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Entry) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Entry) UnmarshalText(data []byte) error {
+	return e.Set(string(data))
+}
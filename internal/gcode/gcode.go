@@ -2,6 +2,7 @@ package gcode
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -14,12 +15,22 @@ type Marshaler interface {
 type Configurator func(*Options)
 
 type Options struct {
-	Deep        float64
-	Feed        float64
-	SecurityZ   float64
-	IgnoreStart bool
-	IgnoreEnd   bool
-	Offset      []float64
+	Deep         float64
+	Feed         float64
+	SecurityZ    float64
+	IgnoreStart  bool
+	IgnoreEnd    bool
+	Offset       []float64
+	Cycle        Cycle
+	Peck         float64
+	ToolDiameter float64
+	PlungeFeed   float64
+	RetractFeed  float64
+	Clearance    float64
+	Entry        Entry
+	RampAngle    float64
+	PreviousDeep float64
+	Spiral       []float64
 }
 
 // WithDeep is a configuration point.
@@ -64,6 +75,70 @@ func WithOffset(offset []float64) Configurator {
 	}
 }
 
+// WithCycle is a configuration point.
+func WithCycle(cycle Cycle) Configurator {
+	return func(o *Options) {
+		o.Cycle = cycle
+	}
+}
+
+// WithToolDiameter is a configuration point.
+func WithToolDiameter(diameter float64) Configurator {
+	return func(o *Options) {
+		o.ToolDiameter = diameter
+	}
+}
+
+// WithPeck is a configuration point.
+func WithPeck(peck float64) Configurator {
+	return func(o *Options) {
+		o.Peck = peck
+	}
+}
+
+// WithPlungeFeed is a configuration point.
+func WithPlungeFeed(feed float64) Configurator {
+	return func(o *Options) {
+		o.PlungeFeed = feed
+	}
+}
+
+// WithRetractFeed is a configuration point.
+func WithRetractFeed(feed float64) Configurator {
+	return func(o *Options) {
+		o.RetractFeed = feed
+	}
+}
+
+// WithClearance is a configuration point.
+func WithClearance(clearance float64) Configurator {
+	return func(o *Options) {
+		o.Clearance = clearance
+	}
+}
+
+// WithEntry is a configuration point. The angle (degrees) is the max slope of the tool going down.
+func WithEntry(entry Entry, angle float64) Configurator {
+	return func(o *Options) {
+		o.Entry = entry
+		o.RampAngle = angle
+	}
+}
+
+// WithPreviousDeep is a configuration point.
+func WithPreviousDeep(deep float64) Configurator {
+	return func(o *Options) {
+		o.PreviousDeep = deep
+	}
+}
+
+// WithSpiral is a configuration point. Closed paths are cut going down continuously, by the deeps.
+func WithSpiral(deeps []float64) Configurator {
+	return func(o *Options) {
+		o.Spiral = deeps
+	}
+}
+
 // Marshal converts any data to gcode.
 // data must implements the Marshaler interface.
 func Marshal(data any, configs ...Configurator) ([]byte, error) {
@@ -91,3 +166,56 @@ func (o Options) OffsetY() float64 {
 
 	return o.Offset[1]
 }
+
+// PlungeRate is the feed moving the tool down in the material. It is the feed when not set.
+func (o Options) PlungeRate() float64 {
+	if o.PlungeFeed > 0 {
+		return o.PlungeFeed
+	}
+
+	return o.Feed
+}
+
+// Slope is the max slope of the tool going down along a path.
+func (o Options) Slope() float64 {
+	return math.Tan(o.RampAngle * math.Pi / 180)
+}
+
+// RPlane is the height where the tool stops moving at rapid speed when going down: the clearance
+// plane when set, or the security Z.
+func (o Options) RPlane() float64 {
+	if o.Clearance > 0 && o.Clearance < o.SecurityZ {
+		return o.Clearance
+	}
+
+	return o.SecurityZ
+}
+
+// Plunge moves the tool down from the security Z: at rapid speed to the clearance plane, then at
+// the plunge feed.
+func (o Options) Plunge(z float64, comment string) string {
+	output := ""
+
+	if plane := o.RPlane(); plane < o.SecurityZ && plane > z {
+		output = fmt.Sprintf("G0 Z%.03f\n", plane)
+	}
+
+	return output + fmt.Sprintf("G1 Z%.03f F%.03f%s\n", z, o.PlungeRate(), lineComment(comment))
+}
+
+// Retract moves the tool up to the security Z: at rapid speed, or at the retract feed when set.
+func (o Options) Retract(comment string) string {
+	if o.RetractFeed > 0 {
+		return fmt.Sprintf("G1 Z%.03f F%.03f%s\n", o.SecurityZ, o.RetractFeed, lineComment(comment))
+	}
+
+	return fmt.Sprintf("G0 Z%.03f%s\n", o.SecurityZ, lineComment(comment))
+}
+
+func lineComment(comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	return "; " + comment
+}
@@ -0,0 +1,26 @@
+package gcode_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsPlunge(t *testing.T) {
+	t.Run("from security Z", func(t *testing.T) {
+		options := gcode.Options{Feed: 100, SecurityZ: 5}
+
+		assert.Equal(t, "G1 Z-2.000 F100.000; Tool down\n", options.Plunge(-2, "Tool down"))
+		assert.Equal(t, "G0 Z5.000; Tool up\n", options.Retract("Tool up"))
+		assert.InDelta(t, 5, options.RPlane(), 1e-9)
+	})
+
+	t.Run("from clearance plane", func(t *testing.T) {
+		options := gcode.Options{Feed: 100, SecurityZ: 5, Clearance: 1, PlungeFeed: 30, RetractFeed: 500}
+
+		assert.Equal(t, "G0 Z1.000\nG1 Z-2.000 F30.000; Tool down\n", options.Plunge(-2, "Tool down"))
+		assert.Equal(t, "G1 Z5.000 F500.000\n", options.Retract(""))
+		assert.InDelta(t, 1, options.RPlane(), 1e-9)
+	})
+}
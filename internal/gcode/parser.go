@@ -0,0 +1,106 @@
+package gcode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Word is a letter with its value.
+type Word struct {
+	Letter byte
+	Value  float64
+}
+
+// Block is a line of a program.
+type Block struct {
+	Line    int
+	Words   []Word
+	Comment string
+}
+
+// Parse reads the blocks of a program. The lines holding neither code nor comment (like the "%"
+// delimiters) are skipped.
+func Parse(in io.Reader) ([]Block, error) {
+	output := []Block{}
+	scanner := bufio.NewScanner(in)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		code, comment := splitComment(strings.TrimSpace(scanner.Text()))
+		if code == "%" {
+			code = ""
+		}
+
+		words, ok := parseWords(code)
+		if !ok {
+			return nil, fmt.Errorf("line %d: cannot read %q", line, code)
+		}
+
+		if len(words) == 0 && comment == "" {
+			continue
+		}
+
+		block := Block{
+			Line:    line,
+			Words:   make([]Word, len(words)),
+			Comment: comment,
+		}
+
+		for idx, current := range words {
+			value, err := strconv.ParseFloat(current.value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: cannot read %c%s", line, current.letter, current.value)
+			}
+
+			block.Words[idx] = Word{Letter: current.letter, Value: value}
+		}
+
+		output = append(output, block)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// Value is the value of the last word of the block with the letter.
+func (b Block) Value(letter byte) (float64, bool) {
+	for idx := len(b.Words) - 1; idx >= 0; idx-- {
+		if b.Words[idx].Letter == letter {
+			return b.Words[idx].Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// Has tells whether the block holds a word with one of the letters.
+func (b Block) Has(letters ...byte) bool {
+	for _, current := range b.Words {
+		if strings.IndexByte(string(letters), current.Letter) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Codes are the values of all the words of the block with the letter, in order.
+func (b Block) Codes(letter byte) []float64 {
+	output := []float64{}
+
+	for _, current := range b.Words {
+		if current.Letter == letter {
+			output = append(output, current.Value)
+		}
+	}
+
+	return output
+}
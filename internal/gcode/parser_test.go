@@ -0,0 +1,40 @@
+package gcode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("blocks", func(t *testing.T) {
+		blocks, err := gcode.Parse(strings.NewReader("%\nN10 G90 G21 (Setup)\n\nG0X1Y-2.5\nG1 Z-1 F100; Tool down\n%\n"))
+		require.NoError(t, err)
+		require.Len(t, blocks, 3)
+
+		assert.Equal(t, 2, blocks[0].Line)
+		assert.Equal(t, []float64{90, 21}, blocks[0].Codes('G'))
+		assert.Equal(t, "Setup", blocks[0].Comment)
+
+		assert.Equal(t, []gcode.Word{{Letter: 'G', Value: 0}, {Letter: 'X', Value: 1}, {Letter: 'Y', Value: -2.5}}, blocks[1].Words)
+		assert.True(t, blocks[1].Has('Z', 'Y'))
+		assert.False(t, blocks[1].Has('Z'))
+
+		feed, ok := blocks[2].Value('F')
+		assert.True(t, ok)
+		assert.InDelta(t, 100, feed, 1e-9)
+		assert.Equal(t, "Tool down", blocks[2].Comment)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := gcode.Parse(strings.NewReader("G90\nG1 X1..2\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+
+		_, err = gcode.Parse(strings.NewReader("G90\n#1=2\n"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,159 @@
+package gcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Post is the post-processor, adapting the gcode to the dialect of a controller.
+type Post int
+
+const (
+	// PostNone keeps the gcode as generated.
+	PostNone Post = iota
+
+	// PostGRBL is for GRBL controllers.
+	PostGRBL
+
+	// PostLinuxCNC is for LinuxCNC.
+	PostLinuxCNC
+
+	// PostMach3 is for Mach3, with arcs given by their radius. The dwells are in seconds, as in the
+	// default configuration of Mach3.
+	PostMach3
+
+	// PostMarlin is for Marlin firmwares.
+	PostMarlin
+
+	// PostFanuc is for generic Fanuc-style controllers.
+	PostFanuc
+)
+
+// defaultModes are the modes set at the start of the programs: absolute positions in millimeters.
+var defaultModes = []string{"G90", "G21"}
+
+// dialect is the way a controller reads gcode. The dwells (G4 P) are in seconds, or in integer
+// milliseconds when dwellMilliseconds is set. The modes are set at the start of the programs,
+// defaultModes being used when none are given.
+type dialect struct {
+	precision         int
+	modes             []string
+	arcRadius         bool
+	lineNumbers       bool
+	parentheses       bool
+	dwellMilliseconds bool
+	start             []string
+	end               []string
+}
+
+// dialects are the gcode dialects of the post-processors.
+var dialects = map[Post]dialect{
+	PostGRBL: {
+		precision: 3,
+		end:       []string{"M30"},
+	},
+	PostLinuxCNC: {
+		precision:   4,
+		parentheses: true,
+		start:       []string{"%"},
+		end:         []string{"M2", "%"},
+	},
+	PostMach3: {
+		precision:   4,
+		arcRadius:   true,
+		lineNumbers: true,
+		parentheses: true,
+		end:         []string{"M30"},
+	},
+	PostMarlin: {
+		precision:         3,
+		dwellMilliseconds: true,
+		end:               []string{"M400"},
+	},
+	PostFanuc: {
+		precision:         3,
+		arcRadius:         true,
+		lineNumbers:       true,
+		parentheses:       true,
+		dwellMilliseconds: true,
+		modes:             []string{"G17 G21 G90 G40 G49 G80"},
+		start:             []string{"%", "O0001"},
+		end:               []string{"M30", "%"},
+	},
+}
+
+// String implements the pflag.Value interface.
+func (p Post) String() string {
+	switch p {
+	case PostNone:
+		return "none"
+	case PostGRBL:
+		return "grbl"
+	case PostLinuxCNC:
+		return "linuxcnc"
+	case PostMach3:
+		return "mach3"
+	case PostMarlin:
+		return "marlin"
+	case PostFanuc:
+		return "fanuc"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (p *Post) Set(value string) error {
+	switch value {
+	case "none", "":
+		*p = PostNone
+	case "grbl":
+		*p = PostGRBL
+	case "linuxcnc":
+		*p = PostLinuxCNC
+	case "mach3":
+		*p = PostMach3
+	case "marlin":
+		*p = PostMarlin
+	case "fanuc":
+		*p = PostFanuc
+	default:
+		return fmt.Errorf("unknown post-processor: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (p Post) Type() string {
+	return "post"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (p Post) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (p *Post) UnmarshalText(data []byte) error {
+	return p.Set(string(data))
+}
+
+// DwellSeconds is the duration in seconds of a dwell written P<value> for the post-processor.
+func (p Post) DwellSeconds(value float64) float64 {
+	if dialects[p].dwellMilliseconds {
+		return value / 1000
+	}
+
+	return value
+}
+
+// Preamble is the start of the programs: the modes of the dialect and the move up to the security Z.
+func (p Post) Preamble(securityZ float64) string {
+	modes := dialects[p].modes
+	if len(modes) == 0 {
+		modes = defaultModes
+	}
+
+	return fmt.Sprintf("%s\nG0 Z%.01f\n", strings.Join(modes, "\n"), securityZ)
+}
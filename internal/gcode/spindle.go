@@ -0,0 +1,181 @@
+package gcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Direction is the rotation of the spindle.
+type Direction int
+
+const (
+	// DirectionClockwise starts the spindle with M3.
+	DirectionClockwise Direction = iota
+
+	// DirectionCounterClockwise starts the spindle with M4.
+	DirectionCounterClockwise
+)
+
+// Coolant is the coolant mode.
+type Coolant int
+
+const (
+	// CoolantOff leaves the coolant off.
+	CoolantOff Coolant = iota
+
+	// CoolantMist starts the mist coolant with M7.
+	CoolantMist
+
+	// CoolantFlood starts the flood coolant with M8.
+	CoolantFlood
+)
+
+// Spindle is the spindle and coolant setup of a program.
+type Spindle struct {
+	Speed     float64
+	Direction Direction
+	Dwell     time.Duration
+	Coolant   Coolant
+	Laser     bool
+}
+
+// Start is the gcode starting the spindle and the coolant. In laser mode, the laser is armed with a
+// null power (M4 sets the power dynamically with the feed), the power being set on each cutting move.
+func (s Spindle) Start() string {
+	var output strings.Builder
+
+	switch {
+	case s.Laser:
+		output.WriteString("M4 S0 ; Laser on\n")
+	case s.Speed > 0:
+		output.WriteString(fmt.Sprintf("%s S%s ; Spindle on\n", s.Direction.code(), number(s.Speed)))
+
+		if s.Dwell > 0 {
+			output.WriteString(fmt.Sprintf("G4 P%s ; Spin up\n", number(s.Dwell.Seconds())))
+		}
+	}
+
+	switch s.Coolant {
+	case CoolantMist:
+		output.WriteString("M7 ; Coolant on\n")
+	case CoolantFlood:
+		output.WriteString("M8 ; Coolant on\n")
+	}
+
+	return output.String()
+}
+
+// Stop is the gcode stopping the spindle and the coolant.
+func (s Spindle) Stop() string {
+	var output strings.Builder
+
+	if s.Laser || s.Speed > 0 {
+		output.WriteString("M5 ; Spindle off\n")
+	}
+
+	if s.Coolant != CoolantOff {
+		output.WriteString("M9 ; Coolant off\n")
+	}
+
+	return output.String()
+}
+
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func (d Direction) code() string {
+	if d == DirectionCounterClockwise {
+		return "M4"
+	}
+
+	return "M3"
+}
+
+// String implements the pflag.Value interface.
+func (d Direction) String() string {
+	switch d {
+	case DirectionClockwise:
+		return "cw"
+	case DirectionCounterClockwise:
+		return "ccw"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (d *Direction) Set(value string) error {
+	switch value {
+	case "cw", "":
+		*d = DirectionClockwise
+	case "ccw":
+		*d = DirectionCounterClockwise
+	default:
+		return fmt.Errorf("unknown spindle direction: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (d Direction) Type() string {
+	return "direction"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Direction) UnmarshalText(data []byte) error {
+	return d.Set(string(data))
+}
+
+// String implements the pflag.Value interface.
+func (c Coolant) String() string {
+	switch c {
+	case CoolantOff:
+		return "off"
+	case CoolantMist:
+		return "mist"
+	case CoolantFlood:
+		return "flood"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (c *Coolant) Set(value string) error {
+	switch value {
+	case "off", "":
+		*c = CoolantOff
+	case "mist":
+		*c = CoolantMist
+	case "flood":
+		*c = CoolantFlood
+	default:
+		return fmt.Errorf("unknown coolant: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (c Coolant) Type() string {
+	return "coolant"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (c Coolant) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *Coolant) UnmarshalText(data []byte) error {
+	return c.Set(string(data))
+}
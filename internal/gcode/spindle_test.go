@@ -0,0 +1,43 @@
+package gcode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpindle(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		assert.Empty(t, gcode.Spindle{}.Start())
+		assert.Empty(t, gcode.Spindle{}.Stop())
+	})
+
+	t.Run("spindle", func(t *testing.T) {
+		spindle := gcode.Spindle{
+			Speed:     12000,
+			Direction: gcode.DirectionCounterClockwise,
+			Dwell:     1500 * time.Millisecond,
+			Coolant:   gcode.CoolantMist,
+		}
+
+		assert.Equal(t, "M4 S12000 ; Spindle on\nG4 P1.5 ; Spin up\nM7 ; Coolant on\n", spindle.Start())
+		assert.Equal(t, "M5 ; Spindle off\nM9 ; Coolant off\n", spindle.Stop())
+	})
+
+	t.Run("laser", func(t *testing.T) {
+		spindle := gcode.Spindle{Speed: 800, Dwell: time.Second, Laser: true}
+
+		assert.Equal(t, "M4 S0 ; Laser on\n", spindle.Start())
+		assert.Equal(t, "M5 ; Spindle off\n", spindle.Stop())
+	})
+}
+
+func TestCoolantSet(t *testing.T) {
+	var coolant gcode.Coolant
+
+	assert.NoError(t, coolant.Set("flood"))
+	assert.Equal(t, gcode.CoolantFlood, coolant)
+	assert.Error(t, coolant.Set("air"))
+}
@@ -0,0 +1,385 @@
+package gcode
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// integerWords are the words whose value is a code or a speed, and is kept as written.
+const integerWords = "GMNOTLHDS"
+
+// Writer adapts the gcode written through it to the dialect of a post-processor. In laser mode,
+// it sets the power on each cutting move.
+type Writer struct {
+	out      io.Writer
+	post     Post
+	dialect  dialect
+	spindle  Spindle
+	pending  []byte
+	started  bool
+	number   int
+	position [2]float64
+	relative bool
+	motion   string
+}
+
+// word is a letter with its value. An exact value is written as is.
+type word struct {
+	letter byte
+	value  string
+	exact  bool
+}
+
+// NewWriter is a builder.
+func NewWriter(out io.Writer, post Post, spindle Spindle) *Writer {
+	return &Writer{
+		out:     out,
+		post:    post,
+		dialect: dialects[post],
+		spindle: spindle,
+	}
+}
+
+// Write implements the io.Writer interface. Lines are written once complete.
+func (w *Writer) Write(data []byte) (int, error) {
+	if w.post == PostNone && !w.spindle.Laser {
+		return w.out.Write(data)
+	}
+
+	w.pending = append(w.pending, data...)
+
+	for {
+		index := bytes.IndexByte(w.pending, '\n')
+		if index < 0 {
+			return len(data), nil
+		}
+
+		line := string(w.pending[:index])
+		w.pending = w.pending[index+1:]
+
+		if err := w.line(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Close writes the last line and ends the program.
+func (w *Writer) Close() error {
+	if w.post == PostNone && !w.spindle.Laser {
+		return nil
+	}
+
+	if len(w.pending) > 0 {
+		if err := w.line(string(w.pending)); err != nil {
+			return err
+		}
+
+		w.pending = nil
+	}
+
+	if !w.started {
+		return nil
+	}
+
+	for _, line := range w.dialect.end {
+		if err := w.emit(line, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) line(text string) error {
+	if !w.started {
+		w.started = true
+
+		for _, line := range w.dialect.start {
+			if _, err := fmt.Fprintf(w.out, "%s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+
+	code, comment := splitComment(strings.TrimSpace(text))
+
+	words, ok := parseWords(code)
+	if !ok {
+		return w.emit(code, comment)
+	}
+
+	if w.spindle.Laser {
+		words = w.laserPower(words)
+	}
+
+	if w.dialect.arcRadius && !w.relative {
+		words = w.arcRadius(words)
+	}
+
+	if w.dialect.dwellMilliseconds {
+		words = dwellMilliseconds(words)
+	}
+
+	fields := make([]string, 0, len(words))
+
+	for _, current := range words {
+		switch {
+		case current.letter == 'G' && current.value == "91":
+			w.relative = true
+		case current.letter == 'G' && current.value == "90":
+			w.relative = false
+		case current.letter == 'X' && !w.relative:
+			w.position[0], _ = strconv.ParseFloat(current.value, 64)
+		case current.letter == 'Y' && !w.relative:
+			w.position[1], _ = strconv.ParseFloat(current.value, 64)
+		}
+
+		fields = append(fields, string(current.letter)+w.format(current))
+	}
+
+	return w.emit(strings.Join(fields, " "), comment)
+}
+
+// emit writes a line, with its line number and its comment.
+func (w *Writer) emit(code string, comment string) error {
+	if code == "" && comment == "" {
+		return nil
+	}
+
+	if code != "" && code != "%" && w.dialect.lineNumbers && !strings.HasPrefix(code, "O") {
+		w.number += 10
+		code = fmt.Sprintf("N%d %s", w.number, code)
+	}
+
+	switch {
+	case comment == "":
+	case w.dialect.parentheses:
+		comment = "(" + strings.NewReplacer("(", "[", ")", "]").Replace(comment) + ")"
+	default:
+		comment = "; " + comment
+	}
+
+	_, err := fmt.Fprintf(w.out, "%s\n", strings.TrimSpace(code+" "+comment))
+
+	return err
+}
+
+// format writes the value of a word with the precision of the dialect.
+func (w *Writer) format(current word) string {
+	if w.post == PostNone || current.exact || strings.IndexByte(integerWords, current.letter) >= 0 {
+		return current.value
+	}
+
+	value, err := strconv.ParseFloat(current.value, 64)
+	if err != nil {
+		return current.value
+	}
+
+	output := strconv.FormatFloat(value, 'f', w.dialect.precision, 64)
+	if strings.Trim(output, "-0.") == "" {
+		return strings.TrimPrefix(output, "-")
+	}
+
+	return output
+}
+
+// laserPower sets the power of the laser on the cutting moves. The laser is off on the moves
+// along Z only.
+func (w *Writer) laserPower(words []word) []word {
+	moving := false
+	cutting := false
+
+	for _, current := range words {
+		switch current.letter {
+		case 'G':
+			switch code, _ := strconv.ParseFloat(current.value, 64); code {
+			case 1, 2, 3:
+				w.motion = current.value
+			case 0, 73, 80, 81, 83:
+				w.motion = ""
+			}
+		case 'X', 'Y':
+			cutting = true
+			moving = true
+		case 'Z':
+			moving = true
+		}
+	}
+
+	if !moving || w.motion == "" {
+		return words
+	}
+
+	power := 0.0
+	if cutting {
+		power = w.spindle.Speed
+	}
+
+	return append(words, word{letter: 'S', value: number(power)})
+}
+
+// dwellMilliseconds gives the duration of a dwell (G4 P, in seconds) in integer milliseconds.
+func dwellMilliseconds(words []word) []word {
+	dwell := false
+
+	for _, current := range words {
+		if current.letter == 'G' && (current.value == "4" || current.value == "04") {
+			dwell = true
+		}
+	}
+
+	if !dwell {
+		return words
+	}
+
+	output := make([]word, len(words))
+
+	for idx, current := range words {
+		output[idx] = current
+
+		if seconds, err := strconv.ParseFloat(current.value, 64); err == nil && current.letter == 'P' {
+			output[idx] = word{letter: 'P', value: strconv.Itoa(int(math.Round(seconds * 1000))), exact: true}
+		}
+	}
+
+	return output
+}
+
+// arcRadius gives the radius form of an arc given with its center (I and J). Full circles keep
+// their center, as a radius cannot tell them.
+func (w *Writer) arcRadius(words []word) []word {
+	values := map[byte]float64{'X': w.position[0], 'Y': w.position[1]}
+	motion := ""
+
+	for _, current := range words {
+		if current.letter == 'G' && (current.value == "2" || current.value == "3" || current.value == "02" || current.value == "03") {
+			motion = strings.TrimPrefix(current.value, "0")
+		}
+
+		if value, err := strconv.ParseFloat(current.value, 64); err == nil {
+			values[current.letter] = value
+		}
+	}
+
+	if motion == "" {
+		return words
+	}
+
+	centerX := w.position[0] + values['I']
+	centerY := w.position[1] + values['J']
+	// The radius is rounded to the precision of the dialect, as the other words.
+	radius := math.Round(math.Hypot(values['I'], values['J'])*math.Pow10(w.dialect.precision)) / math.Pow10(w.dialect.precision)
+
+	if radius == 0 || math.Hypot(values['X']-w.position[0], values['Y']-w.position[1]) < 1e-6 {
+		return words
+	}
+
+	sweep := math.Atan2(values['Y']-centerY, values['X']-centerX) - math.Atan2(w.position[1]-centerY, w.position[0]-centerX)
+	if motion == "2" {
+		sweep = -sweep
+	}
+
+	for sweep <= 0 {
+		sweep += 2 * math.Pi
+	}
+
+	if sweep > math.Pi {
+		radius = -radius
+	}
+
+	output := make([]word, 0, len(words))
+	feeds := []word{}
+
+	for _, current := range words {
+		switch current.letter {
+		case 'I', 'J', 'K':
+		case 'F':
+			feeds = append(feeds, current)
+		default:
+			output = append(output, current)
+		}
+	}
+
+	output = append(output, word{letter: 'R', value: strconv.FormatFloat(radius, 'f', -1, 64)})
+
+	return append(output, feeds...)
+}
+
+// splitComment separates the code of a line from its comments (after a semicolon or between
+// parentheses).
+func splitComment(text string) (string, string) {
+	var (
+		code     strings.Builder
+		comments []string
+	)
+
+	for text != "" {
+		index := strings.IndexAny(text, ";(")
+		if index < 0 {
+			code.WriteString(text)
+
+			break
+		}
+
+		code.WriteString(text[:index])
+
+		if text[index] == ';' {
+			comments = append(comments, strings.TrimSpace(text[index+1:]))
+
+			break
+		}
+
+		end := strings.IndexByte(text[index:], ')')
+		if end < 0 {
+			comments = append(comments, strings.TrimSpace(text[index+1:]))
+
+			break
+		}
+
+		comments = append(comments, strings.TrimSpace(text[index+1:index+end]))
+		text = text[index+end+1:]
+	}
+
+	return strings.TrimSpace(code.String()), strings.Join(comments, " ")
+}
+
+// parseWords reads the words of a line of code. It fails on anything else.
+func parseWords(code string) ([]word, bool) {
+	output := []word{}
+
+	for idx := 0; idx < len(code); {
+		character := code[idx]
+
+		if character == ' ' || character == '\t' {
+			idx++
+
+			continue
+		}
+
+		if character >= 'a' && character <= 'z' {
+			character -= 'a' - 'A'
+		}
+
+		if character < 'A' || character > 'Z' {
+			return nil, false
+		}
+
+		end := idx + 1
+		for end < len(code) && strings.IndexByte("0123456789.+-", code[end]) >= 0 {
+			end++
+		}
+
+		if end == idx+1 {
+			return nil, false
+		}
+
+		output = append(output, word{letter: character, value: code[idx+1 : end]})
+		idx = end
+	}
+
+	return output, true
+}
@@ -0,0 +1,164 @@
+package gcode_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const program = `G90
+G0 X10.000 Y0.000
+G1 Z-1.000 F60.000; Tool down
+;
+G3 X0.000 Y10.000 I-10.000 J0.000 F60.000
+G3 X10.000 Y0.000 I0.000 J-10.000 F60.000
+G2 X10.000 Y0.000 I-10.000 J0.000
+G0X0Y0
+`
+
+func TestWriterNone(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	writer := gcode.NewWriter(output, gcode.PostNone, gcode.Spindle{})
+
+	_, err := fmt.Fprint(writer, program)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, program, output.String())
+}
+
+func TestWriterGRBL(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	writer := gcode.NewWriter(output, gcode.PostGRBL, gcode.Spindle{})
+
+	_, err := fmt.Fprint(writer, program)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, `G90
+G0 X10.000 Y0.000
+G1 Z-1.000 F60.000 ; Tool down
+G3 X0.000 Y10.000 I-10.000 J0.000 F60.000
+G3 X10.000 Y0.000 I0.000 J-10.000 F60.000
+G2 X10.000 Y0.000 I-10.000 J0.000
+G0 X0.000 Y0.000
+M30
+`, output.String())
+}
+
+func TestWriterFanuc(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	writer := gcode.NewWriter(output, gcode.PostFanuc, gcode.Spindle{})
+
+	_, err := fmt.Fprint(writer, program)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, `%
+O0001
+N10 G90
+N20 G0 X10.000 Y0.000
+N30 G1 Z-1.000 F60.000 (Tool down)
+N40 G3 X0.000 Y10.000 R10.000 F60.000
+N50 G3 X10.000 Y0.000 R-10.000 F60.000
+N60 G2 X10.000 Y0.000 I-10.000 J0.000
+N70 G0 X0.000 Y0.000
+N80 M30
+%
+`, output.String())
+}
+
+func TestPostSet(t *testing.T) {
+	var post gcode.Post
+
+	require.NoError(t, post.Set("linuxcnc"))
+	assert.Equal(t, gcode.PostLinuxCNC, post)
+	assert.Error(t, post.Set("heidenhain"))
+}
+
+func TestWriterLaser(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	writer := gcode.NewWriter(output, gcode.PostGRBL, gcode.Spindle{Speed: 800, Laser: true})
+
+	_, err := fmt.Fprint(writer, program)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, `G90
+G0 X10.000 Y0.000
+G1 Z-1.000 F60.000 S0 ; Tool down
+G3 X0.000 Y10.000 I-10.000 J0.000 F60.000 S800
+G3 X10.000 Y0.000 I0.000 J-10.000 F60.000 S800
+G2 X10.000 Y0.000 I-10.000 J0.000 S800
+G0 X0.000 Y0.000
+M30
+`, output.String())
+}
+
+func TestWriterArcRadiusPrecision(t *testing.T) {
+	for post, expected := range map[gcode.Post]string{
+		gcode.PostMach3: "N30 G2 X56.5685 Y0.0000 R-40.0000 F60.0000\n",
+		gcode.PostFanuc: "N30 G2 X56.569 Y0.000 R-40.000 F60.000\n",
+	} {
+		t.Run(post.String(), func(t *testing.T) {
+			output := &bytes.Buffer{}
+
+			writer := gcode.NewWriter(output, post, gcode.Spindle{})
+
+			_, err := fmt.Fprint(writer, "G90\nG0 X0 Y0\nG2 X56.5685425 Y0 I28.2842712 J28.2842712 F60\n")
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			assert.Contains(t, output.String(), expected)
+		})
+	}
+}
+
+func TestWriterDwell(t *testing.T) {
+	spindle := gcode.Spindle{Speed: 1000, Dwell: 1500 * time.Millisecond}
+
+	for post, expected := range map[gcode.Post]string{
+		gcode.PostNone:     "G4 P1.5 ; Spin up\n",
+		gcode.PostGRBL:     "G4 P1.500 ; Spin up\n",
+		gcode.PostLinuxCNC: "G4 P1.5000 (Spin up)\n",
+		gcode.PostMach3:    "N20 G4 P1.5000 (Spin up)\n",
+		gcode.PostMarlin:   "G4 P1500 ; Spin up\n",
+		gcode.PostFanuc:    "N20 G4 P1500 (Spin up)\n",
+	} {
+		t.Run(post.String(), func(t *testing.T) {
+			output := &bytes.Buffer{}
+
+			writer := gcode.NewWriter(output, post, spindle)
+
+			_, err := fmt.Fprint(writer, spindle.Start())
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			assert.Contains(t, output.String(), expected)
+		})
+	}
+}
+
+func TestPostPreamble(t *testing.T) {
+	assert.Equal(t, "G90\nG21\nG0 Z5.0\n", gcode.PostNone.Preamble(5))
+	assert.Equal(t, "G90\nG21\nG0 Z5.0\n", gcode.PostGRBL.Preamble(5))
+
+	output := &bytes.Buffer{}
+
+	writer := gcode.NewWriter(output, gcode.PostFanuc, gcode.Spindle{})
+
+	_, err := fmt.Fprint(writer, gcode.PostFanuc.Preamble(5))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, "%\nO0001\nN10 G17 G21 G90 G40 G49 G80\nN20 G0 Z5.000\nN30 M30\n%\n", output.String())
+}
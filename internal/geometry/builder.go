@@ -49,6 +49,11 @@ func PathsFromDXF(entities ...dxfConfigurator) []Path {
 	return buildPath(output)
 }
 
+// PathsFromLinkers builds a set of paths by chaining the linkers.
+func PathsFromLinkers(linkers ...Linker) []Path {
+	return buildPath(linkers)
+}
+
 // PointsFromDXFPoints builds a set of points.
 func PointsFromDXFPoints(configs ...dxfConfigurator) []Point {
 	dxfFile := dxf{}
@@ -66,6 +71,22 @@ func PointsFromDXFPoints(configs ...dxfConfigurator) []Point {
 		}
 	}
 
+	return PointsFromLinkers(inputPoints...)
+}
+
+// PointsFromLinkers builds a set of points, sorted from the origin.
+func PointsFromLinkers(linkers ...Linker) []Point {
+	inputPoints := []Linker{}
+
+	for _, linker := range linkers {
+		switch value := linker.(type) {
+		case Point:
+			inputPoints = append(inputPoints, value)
+		case *Point:
+			inputPoints = append(inputPoints, *value)
+		}
+	}
+
 	points, _ := SortEntities(inputPoints, &Coordinates{X: 0, Y: 0}, func(from, to Linker) bool {
 		return true
 	})
@@ -123,3 +123,41 @@ func (c Coordinates) Box() Box {
 		Max: c,
 	}
 }
+
+func (c Coordinates) add(other Coordinates) Coordinates {
+	return Coordinates{X: c.X + other.X, Y: c.Y + other.Y}
+}
+
+func (c Coordinates) sub(other Coordinates) Coordinates {
+	return Coordinates{X: c.X - other.X, Y: c.Y - other.Y}
+}
+
+func (c Coordinates) scale(factor float64) Coordinates {
+	return Coordinates{X: c.X * factor, Y: c.Y * factor}
+}
+
+func (c Coordinates) dot(other Coordinates) float64 {
+	return c.X*other.X + c.Y*other.Y
+}
+
+func (c Coordinates) cross(other Coordinates) float64 {
+	return c.X*other.Y - c.Y*other.X
+}
+
+func (c Coordinates) norm() float64 {
+	return math.Sqrt(c.X*c.X + c.Y*c.Y)
+}
+
+func (c Coordinates) unit() Coordinates {
+	length := c.norm()
+	if length == 0 {
+		return Coordinates{}
+	}
+
+	return c.scale(1 / length)
+}
+
+// leftNormal is the vector rotated by 90° counter-clockwise.
+func (c Coordinates) leftNormal() Coordinates {
+	return Coordinates{X: -c.Y, Y: c.X}
+}
@@ -154,11 +154,10 @@ func (c Curve) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 	if !options.IgnoreStart {
 		start := c.Start()
 		output = fmt.Sprintf(
-			"G0 X%.03f Y%.03f\nG1 Z%.03f F%.03f ; Tool down\n",
+			"G0 X%.03f Y%.03f\n%s",
 			start.X-options.OffsetX(),
 			start.Y-options.OffsetY(),
-			-options.Deep,
-			options.Feed,
+			options.Plunge(-options.Deep, "Tool down"),
 		)
 	}
 
@@ -179,3 +178,107 @@ func (c Curve) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 
 	return []byte(output), nil
 }
+
+// counterClockwise tells whether the curve is machined with G3.
+func (c Curve) counterClockwise() bool {
+	return c.Clockwise
+}
+
+// Sweep is the signed angle (radians) covered by the curve, positive when counter-clockwise.
+func (c Curve) Sweep() float64 {
+	angleStart := math.Atan2(c.StartPoint.Y-c.Center.Y, c.StartPoint.X-c.Center.X)
+	angleEnd := math.Atan2(c.EndPoint.Y-c.Center.Y, c.EndPoint.X-c.Center.X)
+
+	angleDiff := angleEnd - angleStart
+
+	if c.counterClockwise() {
+		for angleDiff <= 0 {
+			angleDiff += 2 * math.Pi
+		}
+
+		return angleDiff
+	}
+
+	for angleDiff >= 0 {
+		angleDiff -= 2 * math.Pi
+	}
+
+	return angleDiff
+}
+
+// Length is the length of the curve.
+func (c Curve) Length() float64 {
+	return math.Abs(c.Sweep()) * c.Radius
+}
+
+// tangentAt is the unit direction of the tool when passing on point.
+func (c Curve) tangentAt(point Coordinates) Coordinates {
+	tangent := point.sub(c.Center).unit().leftNormal()
+	if !c.counterClockwise() {
+		return tangent.scale(-1)
+	}
+
+	return tangent
+}
+
+// pointAt gives the point of the curve at a position (from 0 at start to 1 at end).
+func (c Curve) pointAt(position float64) Coordinates {
+	angle := math.Atan2(c.StartPoint.Y-c.Center.Y, c.StartPoint.X-c.Center.X) + c.Sweep()*position
+
+	return Coordinates{
+		X: c.Center.X + c.Radius*math.Cos(angle),
+		Y: c.Center.Y + c.Radius*math.Sin(angle),
+	}
+}
+
+// Split cuts the curve at a position (from 0 at start to 1 at end).
+func (c Curve) Split(position float64) (*Curve, *Curve) {
+	middle := c.pointAt(position)
+
+	return &Curve{
+		Name:       c.Name,
+		StartPoint: c.StartPoint,
+		EndPoint:   middle,
+		Center:     c.Center,
+		Radius:     c.Radius,
+		Clockwise:  c.Clockwise,
+	}, &Curve{
+		Name:       c.Name,
+		StartPoint: middle,
+		EndPoint:   c.EndPoint,
+		Center:     c.Center,
+		Radius:     c.Radius,
+		Clockwise:  c.Clockwise,
+	}
+}
+
+// project gives the position (from 0 at start to 1 at end) of the nearest point of the curve.
+func (c Curve) project(point Coordinates) float64 {
+	sweep := c.Sweep()
+	if sweep == 0 {
+		return 0
+	}
+
+	angleStart := math.Atan2(c.StartPoint.Y-c.Center.Y, c.StartPoint.X-c.Center.X)
+	angle := math.Atan2(point.Y-c.Center.Y, point.X-c.Center.X) - angleStart
+
+	if sweep > 0 {
+		for angle < 0 {
+			angle += 2 * math.Pi
+		}
+	} else {
+		for angle > 0 {
+			angle -= 2 * math.Pi
+		}
+	}
+
+	if position := angle / sweep; position <= 1 {
+		return position
+	}
+
+	if point.DistanceTo(c.StartPoint) < point.DistanceTo(c.EndPoint) {
+		return 0
+	}
+
+	return 1
+}
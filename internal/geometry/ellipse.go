@@ -0,0 +1,41 @@
+package geometry
+
+import "math"
+
+// NewPathFromEllipse is a builder. The ellipse is given by its center, the end of its major axis
+// (relative to the center), the ratio of the minor axis to the major axis, and the parameters
+// where it starts and ends. It goes counterclockwise, unless clockwise is set.
+func NewPathFromEllipse(
+	name string,
+	center Coordinates,
+	major Coordinates,
+	ratio float64,
+	start float64,
+	end float64,
+	clockwise bool,
+	tolerance float64,
+) *Path {
+	minor := major.leftNormal().scale(ratio)
+	if clockwise {
+		minor = minor.scale(-1)
+	}
+
+	for end <= start {
+		end += 2 * math.Pi
+	}
+
+	breaks := []float64{start}
+
+	// Quarters of the ellipse are fitted separately, as the curvature changes its trend at each one.
+	for quarter := math.Ceil(start/(math.Pi/2)+epsilon) * math.Pi / 2; quarter < end-epsilon; quarter += math.Pi / 2 {
+		breaks = append(breaks, quarter)
+	}
+
+	breaks = append(breaks, end)
+
+	path := FitCurve(name, func(parameter float64) Coordinates {
+		return center.add(major.scale(math.Cos(parameter))).add(minor.scale(math.Sin(parameter)))
+	}, breaks, tolerance)
+
+	return &path
+}
@@ -0,0 +1,154 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+)
+
+// Descent is a move of the tool going down along an element (a segment or a curve) of a path, to
+// the height Z.
+type Descent struct {
+	Linker Linker
+	Z      float64
+}
+
+// Entry gives the moves taking the tool from one height down to a lower one, starting and ending at
+// the start of the path, and going down no steeper than the slope. Ramps go back and forth along the
+// first elements of the path, and helixes go around closed paths. Nothing is given for a plunge, or
+// when the path cannot be followed.
+func (p Path) Entry(entry gcode.Entry, from float64, to float64, slope float64) []Descent {
+	if entry == gcode.EntryPlunge || slope <= 0 || from-to < epsilon {
+		return nil
+	}
+
+	elements := p.flatten()
+
+	length := elements.Length()
+	if length < epsilon {
+		return nil
+	}
+
+	distance := (from - to) / slope
+	moves := Path{}
+
+	if entry == gcode.EntryHelix && p.IsClosed() {
+		for range max(1, int(math.Ceil(distance/length-epsilon))) {
+			moves = append(moves, elements...)
+		}
+	} else {
+		leg := math.Min(distance/2, length)
+		forward := elements.head(leg)
+		backward := forward.reversed()
+
+		for range max(1, int(math.Ceil(distance/(2*leg)-epsilon))) {
+			moves = append(moves, forward...)
+			moves = append(moves, backward...)
+		}
+	}
+
+	total := moves.Length()
+	position := 0.0
+	output := make([]Descent, len(moves))
+
+	for idx, elt := range moves {
+		position += linkerLength(elt)
+		output[idx] = Descent{Linker: elt, Z: from - (from-to)*position/total}
+	}
+
+	output[len(output)-1].Z = to
+
+	return output
+}
+
+// MarshallGCode implements the Marshaler interface.
+func (d Descent) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
+	options := gcode.Options{}
+	for _, config := range configs {
+		config(&options)
+	}
+
+	switch value := d.Linker.(type) {
+	case *Segment:
+		return []byte(fmt.Sprintf(
+			"G1 X%.03f Y%.03f Z%.03f F%.03f\n",
+			value.EndPoint.X-options.OffsetX(),
+			value.EndPoint.Y-options.OffsetY(),
+			d.Z,
+			options.Feed,
+		)), nil
+	case *Curve:
+		code := 2
+		if value.Clockwise {
+			code = 3
+		}
+
+		return []byte(fmt.Sprintf(
+			"G%d X%.03f Y%.03f Z%.03f I%.03f J%.03f F%.03f\n",
+			code,
+			value.EndPoint.X-options.OffsetX(),
+			value.EndPoint.Y-options.OffsetY(),
+			d.Z,
+			value.Center.X-value.StartPoint.X,
+			value.Center.Y-value.StartPoint.Y,
+			options.Feed,
+		)), nil
+	}
+
+	return nil, fmt.Errorf("cannot go down along %T", d.Linker)
+}
+
+// flatten gives the segments and the curves of the path, those of the tabs included.
+func (p Path) flatten() Path {
+	output := Path{}
+
+	for _, elt := range p.Unfold() {
+		if tab, ok := elt.(*Tab); ok {
+			output = append(output, tab.Elements.flatten()...)
+
+			continue
+		}
+
+		output = append(output, elt)
+	}
+
+	return output
+}
+
+// head gives the elements of the path up to a distance from its start.
+func (p Path) head(length float64) Path {
+	output := Path{}
+	position := 0.0
+
+	for _, elt := range p.splitAt([]float64{length}) {
+		if position >= length-epsilon {
+			break
+		}
+
+		output = append(output, elt)
+		position += linkerLength(elt)
+	}
+
+	return output
+}
+
+// reversed gives a reverted copy of the path.
+func (p Path) reversed() Path {
+	output := make(Path, 0, len(p))
+
+	for idx := len(p) - 1; idx >= 0; idx-- {
+		switch value := p[idx].(type) {
+		case *Segment:
+			reverted := *value
+			reverted.Revert()
+			output = append(output, &reverted)
+		case *Curve:
+			reverted := *value
+			reverted.Revert()
+			output = append(output, &reverted)
+		}
+	}
+
+	return output
+}
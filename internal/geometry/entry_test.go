@@ -0,0 +1,50 @@
+package geometry_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathEntry(t *testing.T) {
+	t.Run("plunge", func(t *testing.T) {
+		assert.Empty(t, square(10).Entry(gcode.EntryPlunge, 0, -1, 0.1))
+	})
+
+	t.Run("ramp", func(t *testing.T) {
+		// 1 mm down at 10% needs 10 mm: 5 mm forward and 5 mm back.
+		descents := square(10).Entry(gcode.EntryRamp, 0, -1, 0.1)
+		require.Len(t, descents, 2)
+
+		assert.Equal(t, geometry.Coordinates{X: 5, Y: 0}, *descents[0].Linker.End())
+		assert.InDelta(t, -0.5, descents[0].Z, 1e-9)
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: 0}, *descents[1].Linker.End())
+		assert.InDelta(t, -1, descents[1].Z, 1e-9)
+	})
+
+	t.Run("ramp back and forth", func(t *testing.T) {
+		path := geometry.Path{&geometry.Segment{StartPoint: geometry.Coordinates{}, EndPoint: geometry.Coordinates{X: 2}}}
+
+		descents := path.Entry(gcode.EntryRamp, -1, -2, 0.1)
+		require.Len(t, descents, 6)
+
+		for idx, descent := range descents {
+			assert.InDelta(t, -1-float64(idx+1)/6, descent.Z, 1e-9)
+		}
+
+		assert.Equal(t, geometry.Coordinates{}, *descents[5].Linker.End())
+	})
+
+	t.Run("helix", func(t *testing.T) {
+		// 1 mm down at 2% needs 50 mm: two turns of the square.
+		descents := square(10).Entry(gcode.EntryHelix, 0, -1, 0.02)
+		require.Len(t, descents, 8)
+
+		assert.InDelta(t, -0.5, descents[3].Z, 1e-9)
+		assert.InDelta(t, -1, descents[7].Z, 1e-9)
+		assert.Equal(t, geometry.Coordinates{}, *descents[7].Linker.End())
+	})
+}
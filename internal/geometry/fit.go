@@ -0,0 +1,182 @@
+package geometry
+
+import "math"
+
+const (
+	// fitSamples is the number of points checked on each part of a fitted curve.
+	fitSamples = 16
+
+	// fitDepth limits the number of times a part of a curve is split before falling back to segments.
+	fitDepth = 12
+)
+
+// FitCurve approximates a parametric curve, between the parameters of breaks, with bi-arcs.
+// Breaks are the places where the curve may not be smooth. Parts where arcs do not fit within
+// the tolerance are approximated with segments.
+func FitCurve(name string, at func(float64) Coordinates, breaks []float64, tolerance float64) Path {
+	fitter := curveFitter{
+		name:      name,
+		at:        at,
+		tolerance: tolerance,
+	}
+
+	output := Path{}
+
+	for idx := 1; idx < len(breaks); idx++ {
+		if breaks[idx] > breaks[idx-1] {
+			output = append(output, fitter.fit(breaks[idx-1], breaks[idx], 0)...)
+		}
+	}
+
+	return output
+}
+
+type curveFitter struct {
+	name      string
+	at        func(float64) Coordinates
+	tolerance float64
+}
+
+// tangent estimates the unit direction of the curve at one end of [from, to], with a second order
+// difference taken inside the range.
+func (f curveFitter) tangent(parameter float64, from float64, to float64) Coordinates {
+	step := (to - from) * 1e-5
+	if parameter > from {
+		step = -step
+	}
+
+	difference := f.at(parameter).scale(-3).add(f.at(parameter + step).scale(4)).sub(f.at(parameter + 2*step))
+	if step < 0 {
+		return difference.scale(-1).unit()
+	}
+
+	return difference.unit()
+}
+
+func (f curveFitter) fit(from float64, to float64, depth int) Path {
+	start := f.at(from)
+	end := f.at(to)
+
+	if arcs, ok := f.biarc(start, f.tangent(from, from, to), end, f.tangent(to, from, to)); ok && f.close(arcs, from, to) {
+		return arcs
+	}
+
+	if depth >= fitDepth {
+		return f.segments(from, to, 0)
+	}
+
+	middle := (from + to) / 2
+
+	return append(f.fit(from, middle, depth+1), f.fit(middle, to, depth+1)...)
+}
+
+// close checks whether the curve between the parameters stays within the tolerance of the path.
+func (f curveFitter) close(path Path, from float64, to float64) bool {
+	for idx := 1; idx < fitSamples; idx++ {
+		if _, distance := path.Project(f.at(from + (to-from)*float64(idx)/fitSamples)); distance > f.tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// segments approximates the curve with segments whose middle stays within the tolerance of the curve.
+func (f curveFitter) segments(from float64, to float64, depth int) Path {
+	start := f.at(from)
+	end := f.at(to)
+	middle := (from + to) / 2
+
+	chord := Segment{StartPoint: start, EndPoint: end}
+	if position := chord.project(f.at(middle)); depth >= fitDepth ||
+		f.at(middle).DistanceTo(start.add(end.sub(start).scale(position))) <= f.tolerance {
+		if start.Equal(end) {
+			return Path{}
+		}
+
+		return Path{&Segment{Name: f.name, StartPoint: start, EndPoint: end}}
+	}
+
+	return append(f.segments(from, middle, depth+1), f.segments(middle, to, depth+1)...)
+}
+
+// biarc joins two points with two tangent arcs, leaving the first point along the first tangent and
+// reaching the second point along the second tangent.
+func (f curveFitter) biarc(start Coordinates, startTangent Coordinates, end Coordinates, endTangent Coordinates) (Path, bool) {
+	chord := end.sub(start)
+
+	if chord.norm() < epsilon || startTangent.norm() < epsilon || endTangent.norm() < epsilon {
+		return nil, false
+	}
+
+	sum := startTangent.add(endTangent)
+	a := 2 * (1 - startTangent.dot(endTangent))
+	b := chord.dot(sum)
+
+	var distance float64
+
+	if a < epsilon {
+		if b <= epsilon {
+			return nil, false
+		}
+
+		distance = chord.dot(chord) / (2 * b)
+	} else {
+		distance = (-b + math.Sqrt(b*b+a*chord.dot(chord))) / a
+	}
+
+	if distance <= epsilon {
+		return nil, false
+	}
+
+	joint := start.add(startTangent.scale(distance)).add(end.sub(endTangent.scale(distance))).scale(0.5)
+
+	first, ok := f.arc(start, startTangent, joint, false)
+	if !ok {
+		return nil, false
+	}
+
+	second, ok := f.arc(end, endTangent.scale(-1), joint, true)
+	if !ok {
+		return nil, false
+	}
+
+	return Path{first, second}, true
+}
+
+// arc builds the arc leaving point along tangent and reaching other. When reverse is set,
+// the arc is turned around to go from other to point.
+func (f curveFitter) arc(point Coordinates, tangent Coordinates, other Coordinates, reverse bool) (Linker, bool) {
+	chord := other.sub(point)
+	normal := tangent.leftNormal()
+	offset := normal.dot(chord)
+
+	if math.Abs(offset) < epsilon*chord.norm() {
+		segment := &Segment{Name: f.name, StartPoint: point, EndPoint: other}
+		if reverse {
+			segment.Revert()
+		}
+
+		return segment, true
+	}
+
+	radius := chord.dot(chord) / (2 * offset)
+	curve := &Curve{
+		Name:       f.name,
+		StartPoint: point,
+		EndPoint:   other,
+		Center:     point.add(normal.scale(radius)),
+		Radius:     math.Abs(radius),
+		Clockwise:  radius > 0,
+	}
+
+	if math.Abs(curve.Sweep()) > math.Pi {
+		return nil, false
+	}
+
+	if reverse {
+		curve.Revert()
+	}
+
+	return curve, true
+}
@@ -0,0 +1,96 @@
+package geometry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPathFromEllipse(t *testing.T) {
+	center := geometry.Coordinates{X: 10, Y: 20}
+	major := geometry.Coordinates{X: 30, Y: 0}
+
+	path := geometry.NewPathFromEllipse("ellipse", center, major, 0.5, 0, 2*math.Pi, false, 0.01)
+	require.NotEmpty(t, *path)
+	assert.True(t, path.IsClosed())
+
+	for _, linker := range *path {
+		_, ok := linker.(*geometry.Curve)
+		assert.True(t, ok)
+	}
+
+	for idx := range 100 {
+		angle := 2 * math.Pi * float64(idx) / 100
+		point := geometry.Coordinates{X: 10 + 30*math.Cos(angle), Y: 20 + 15*math.Sin(angle)}
+
+		_, distance := path.Project(point)
+		assert.Less(t, distance, 0.01)
+	}
+
+	assert.Greater(t, path.Area(), 0.0)
+	assert.InDelta(t, math.Pi*30*15, math.Abs(path.Area()), 1)
+}
+
+func TestNewPathFromEllipseCircle(t *testing.T) {
+	path := geometry.NewPathFromEllipse(
+		"circle",
+		geometry.Coordinates{},
+		geometry.Coordinates{X: 0, Y: 10},
+		1,
+		0,
+		math.Pi,
+		true,
+		0.01,
+	)
+
+	require.NotEmpty(t, *path)
+	assert.InDelta(t, 0, path.Start().X, 1e-9)
+	assert.InDelta(t, 10, path.Start().Y, 1e-9)
+	assert.InDelta(t, 0, path.End().X, 1e-9)
+	assert.InDelta(t, -10, path.End().Y, 1e-9)
+
+	for _, linker := range *path {
+		curve, ok := linker.(*geometry.Curve)
+		require.True(t, ok)
+		assert.InDelta(t, 10, curve.Radius, 1e-6)
+		assert.False(t, curve.Clockwise)
+	}
+}
+
+func TestNewPathFromSpline(t *testing.T) {
+	controls := []geometry.Coordinates{{X: 0, Y: 0}, {X: 10, Y: 20}, {X: 30, Y: 20}, {X: 40, Y: 0}}
+	knots := []float64{0, 0, 0, 0, 1, 1, 1, 1}
+
+	path := geometry.NewPathFromSpline("spline", 3, knots, controls, nil, nil, 0.01)
+	require.NotEmpty(t, *path)
+
+	assert.Equal(t, controls[0], *path.Start())
+	assert.InDelta(t, 40, path.End().X, 1e-9)
+	assert.InDelta(t, 0, path.End().Y, 1e-9)
+
+	// Bezier curve with the same control points.
+	for idx := range 50 {
+		u := float64(idx) / 50
+		coefficients := []float64{math.Pow(1-u, 3), 3 * u * math.Pow(1-u, 2), 3 * u * u * (1 - u), u * u * u}
+
+		point := geometry.Coordinates{}
+		for rank, control := range controls {
+			point.X += coefficients[rank] * control.X
+			point.Y += coefficients[rank] * control.Y
+		}
+
+		_, distance := path.Project(point)
+		assert.Less(t, distance, 0.01)
+	}
+}
+
+func TestNewPathFromSplineFitPoints(t *testing.T) {
+	fits := []geometry.Coordinates{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}
+
+	path := geometry.NewPathFromSpline("spline", 3, nil, nil, nil, fits, 0.01)
+	require.Len(t, *path, 2)
+	assert.Equal(t, fits[2], *path.End())
+}
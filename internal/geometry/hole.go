@@ -0,0 +1,119 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+)
+
+// holeTolerance is the diameter tolerance to group holes and to match the tool.
+const holeTolerance = 0.01
+
+// Hole is a circular hole.
+type Hole struct {
+	Point
+	Diameter float64
+}
+
+// GroupHoles sorts the holes into groups of the same diameter. Groups are sorted by increasing
+// diameter, and the holes of a group are sorted from the origin.
+func GroupHoles(holes ...Hole) [][]Hole {
+	groups := map[int64][]Linker{}
+
+	for _, hole := range holes {
+		key := int64(math.Round(hole.Diameter / holeTolerance))
+
+		groups[key] = append(groups[key], hole)
+	}
+
+	keys := make([]int64, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	output := make([][]Hole, len(keys))
+
+	for idx, key := range keys {
+		sorted, _ := SortEntities(groups[key], &Coordinates{X: 0, Y: 0}, func(from, to Linker) bool {
+			return true
+		})
+
+		for _, hole := range sorted {
+			if value, ok := hole.(Hole); ok {
+				output[idx] = append(output[idx], value)
+			}
+		}
+	}
+
+	return output
+}
+
+// Helical tells whether the hole is larger than the tool and must be interpolated.
+func (h Hole) Helical(toolDiameter float64) bool {
+	return toolDiameter > 0 && h.Diameter > toolDiameter+holeTolerance
+}
+
+// Undersized tells whether the hole is smaller than the tool, which would drill it oversize.
+func (h Hole) Undersized(toolDiameter float64) bool {
+	return toolDiameter > 0 && h.Diameter < toolDiameter-holeTolerance
+}
+
+// MarshallGCode implements the Marshaler interface.
+// Holes larger than the tool are interpolated with a helix going down by the peck on each turn.
+func (h Hole) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
+	options := gcode.Options{}
+	for _, config := range configs {
+		config(&options)
+	}
+
+	if !h.Helical(options.ToolDiameter) {
+		return h.Point.MarshallGCode(configs...)
+	}
+
+	x := h.X - options.OffsetX()
+	y := h.Y - options.OffsetY()
+	radius := (h.Diameter - options.ToolDiameter) / 2
+
+	turns := 1
+	if options.Peck > 0 {
+		turns = int(math.Ceil(options.Deep / options.Peck))
+	}
+
+	output := fmt.Sprintf(
+		";------ Hole %s D%.03f\nG0 X%.03f Y%.03f\n%s",
+		h.Name,
+		h.Diameter,
+		x+radius,
+		y,
+		options.Plunge(0, ""),
+	)
+
+	for turn := 1; turn <= turns; turn++ {
+		output += fmt.Sprintf(
+			"G3 X%.03f Y%.03f Z%.03f I%.03f J0.000 F%.03f\n",
+			x+radius,
+			y,
+			-options.Deep*float64(turn)/float64(turns),
+			-radius,
+			options.Feed,
+		)
+	}
+
+	output += fmt.Sprintf(
+		"G3 X%.03f Y%.03f I%.03f J0.000 F%.03f; Clean bottom\nG1 X%.03f Y%.03f F%.03f\n%s",
+		x+radius,
+		y,
+		-radius,
+		options.Feed,
+		x,
+		y,
+		options.Feed,
+		options.Retract("Tool up"),
+	)
+
+	return []byte(output), nil
+}
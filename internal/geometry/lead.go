@@ -0,0 +1,170 @@
+package geometry
+
+import "fmt"
+
+// Lead is the shape of the moves taking the tool onto and off closed paths.
+type Lead int
+
+const (
+	// LeadNone starts and ends the cut on the path.
+	LeadNone Lead = iota
+
+	// LeadArc joins the path with a tangential quarter of circle.
+	LeadArc
+
+	// LeadLine joins the path with a line perpendicular to it.
+	LeadLine
+)
+
+// WithLeads adds a lead-in before the closed path and a lead-out after it, on the waste side (out of
+// the path when outside is set, in the path otherwise), the cut going past the start by overlap.
+// The length is the radius of the arcs, or the length of the lines. Open paths are kept as is.
+func (p Path) WithLeads(lead Lead, length float64, overlap float64, outside bool) Path {
+	if lead == LeadNone || length <= 0 || !p.IsClosed() {
+		return p
+	}
+
+	flat := p.flatten()
+	if len(flat) == 0 {
+		return p
+	}
+
+	sign := 1.0
+	if (p.Area() > 0) == outside {
+		sign = -1
+	}
+
+	name := linkerName(flat[0])
+
+	start := *flat.Start()
+	startDirection := startTangent(flat[0])
+	leadIn := lead.join(start, startDirection, startDirection.leftNormal().scale(sign*length), true)
+	setName(leadIn, name+" (lead-in)")
+
+	tail := Path{}
+	if overlap > 0 {
+		tail = p.Unfold().head(overlap).copied()
+	}
+
+	last := append(flat, tail.flatten()...)
+	end := *last.End()
+	endDirection := endTangent(last[len(last)-1])
+	leadOut := lead.join(end, endDirection, endDirection.leftNormal().scale(sign*length), false)
+	setName(leadOut, name+" (lead-out)")
+
+	output := Path{leadIn}
+	output = append(output, p...)
+	output = append(output, tail...)
+
+	return append(output, leadOut)
+}
+
+// join builds the lead reaching point (or leaving it when entering is not set), the tool going along
+// direction on point. The lead stands on the side of normal, whose length is the size of the lead.
+func (l Lead) join(point Coordinates, direction Coordinates, normal Coordinates, entering bool) Linker {
+	if l == LeadLine {
+		if entering {
+			return &Segment{StartPoint: point.add(normal), EndPoint: point}
+		}
+
+		return &Segment{StartPoint: point, EndPoint: point.add(normal)}
+	}
+
+	radius := normal.norm()
+	center := point.add(normal)
+	counterClockwise := normal.scale(-1).cross(direction) > 0
+
+	if entering {
+		return &Curve{
+			StartPoint: center.sub(direction.scale(radius)),
+			EndPoint:   point,
+			Center:     center,
+			Radius:     radius,
+			Clockwise:  counterClockwise,
+		}
+	}
+
+	return &Curve{
+		StartPoint: point,
+		EndPoint:   center.add(direction.scale(radius)),
+		Center:     center,
+		Radius:     radius,
+		Clockwise:  counterClockwise,
+	}
+}
+
+// copied gives a copy of the path, the elements of which can be changed apart.
+func (p Path) copied() Path {
+	output := make(Path, 0, len(p))
+
+	for _, elt := range p {
+		switch value := elt.(type) {
+		case *Segment:
+			copied := *value
+			output = append(output, &copied)
+		case *Curve:
+			copied := *value
+			output = append(output, &copied)
+		case *Tab:
+			output = append(output, &Tab{Elements: value.Elements.copied(), Top: value.Top})
+		default:
+			output = append(output, elt)
+		}
+	}
+
+	return output
+}
+
+func setName(elt Linker, name string) {
+	switch value := elt.(type) {
+	case *Segment:
+		value.Name = name
+	case *Curve:
+		value.Name = name
+	}
+}
+
+// String implements the pflag.Value interface.
+func (l Lead) String() string {
+	switch l {
+	case LeadNone:
+		return "none"
+	case LeadArc:
+		return "arc"
+	case LeadLine:
+		return "line"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (l *Lead) Set(value string) error {
+	switch value {
+	case "none", "":
+		*l = LeadNone
+	case "arc":
+		*l = LeadArc
+	case "line":
+		*l = LeadLine
+	default:
+		return fmt.Errorf("unknown lead: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (l Lead) Type() string {
+	return "lead"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (l Lead) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (l *Lead) UnmarshalText(data []byte) error {
+	return l.Set(string(data))
+}
@@ -0,0 +1,54 @@
+package geometry_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathWithLeads(t *testing.T) {
+	t.Run("line outside", func(t *testing.T) {
+		output := square(10).WithLeads(geometry.LeadLine, 2, 0, true)
+		require.Len(t, output, 6)
+
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: -2}, *output.Start())
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: 0}, *output[0].End())
+		assert.Equal(t, geometry.Coordinates{X: -2, Y: 0}, *output.End())
+	})
+
+	t.Run("line inside", func(t *testing.T) {
+		output := square(10).WithLeads(geometry.LeadLine, 2, 0, false)
+
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: 2}, *output.Start())
+		assert.Equal(t, geometry.Coordinates{X: 2, Y: 0}, *output.End())
+	})
+
+	t.Run("arc with overlap", func(t *testing.T) {
+		output := square(10).WithLeads(geometry.LeadArc, 2, 3, true)
+		require.Len(t, output, 7)
+
+		leadIn, ok := output[0].(*geometry.Curve)
+		require.True(t, ok)
+		assert.Equal(t, geometry.Coordinates{X: -2, Y: -2}, leadIn.StartPoint)
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: -2}, leadIn.Center)
+		assert.False(t, leadIn.Clockwise)
+
+		assert.Equal(t, geometry.Coordinates{X: 3, Y: 0}, *output[5].End())
+
+		leadOut, ok := output[6].(*geometry.Curve)
+		require.True(t, ok)
+		assert.Equal(t, geometry.Coordinates{X: 3, Y: -2}, leadOut.Center)
+		assert.Equal(t, geometry.Coordinates{X: 5, Y: -2}, leadOut.EndPoint)
+		assert.False(t, leadOut.Clockwise)
+	})
+
+	t.Run("open path", func(t *testing.T) {
+		path := geometry.Path{
+			&geometry.Segment{StartPoint: geometry.Coordinates{X: 0, Y: 0}, EndPoint: geometry.Coordinates{X: 10, Y: 0}},
+		}
+
+		assert.Equal(t, path, path.WithLeads(geometry.LeadArc, 2, 1, true))
+	})
+}
@@ -0,0 +1,89 @@
+package geometry
+
+import (
+	"fmt"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+)
+
+// Milling is the cutting direction of the tool along closed paths.
+type Milling int
+
+const (
+	// MillingAny keeps the direction of the drawing.
+	MillingAny Milling = iota
+
+	// MillingClimb cuts with the tool edge entering the material at the full chip thickness.
+	MillingClimb
+
+	// MillingConventional cuts with the tool edge entering the material at a null chip thickness.
+	MillingConventional
+)
+
+// Clockwise tells whether a closed path must be followed clockwise. Outside profiles leave the
+// material inside the path, inside profiles leave it outside.
+// With a clockwise spindle, climb milling follows outside profiles clockwise and inside profiles
+// counter-clockwise.
+func (m Milling) Clockwise(outside bool, spindle gcode.Direction) bool {
+	return (m == MillingClimb) == outside == (spindle == gcode.DirectionClockwise)
+}
+
+// IsClockwise tells whether the closed path turns clockwise.
+func (p Path) IsClockwise() bool {
+	return p.Area() < 0
+}
+
+// Wound gives the closed path turning clockwise or counter-clockwise, reverting it if needed.
+// Open paths are kept as is.
+func (p Path) Wound(clockwise bool) Path {
+	if !p.IsClosed() || p.IsClockwise() == clockwise {
+		return p
+	}
+
+	return p.Unfold().reversed()
+}
+
+// String implements the pflag.Value interface.
+func (m Milling) String() string {
+	switch m {
+	case MillingAny:
+		return "any"
+	case MillingClimb:
+		return "climb"
+	case MillingConventional:
+		return "conventional"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (m *Milling) Set(value string) error {
+	switch value {
+	case "any", "":
+		*m = MillingAny
+	case "climb":
+		*m = MillingClimb
+	case "conventional":
+		*m = MillingConventional
+	default:
+		return fmt.Errorf("unknown milling: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (m Milling) Type() string {
+	return "milling"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (m Milling) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (m *Milling) UnmarshalText(data []byte) error {
+	return m.Set(string(data))
+}
@@ -0,0 +1,50 @@
+package geometry_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMillingClockwise(t *testing.T) {
+	assert.True(t, geometry.MillingClimb.Clockwise(true, gcode.DirectionClockwise))
+	assert.False(t, geometry.MillingClimb.Clockwise(false, gcode.DirectionClockwise))
+	assert.False(t, geometry.MillingConventional.Clockwise(true, gcode.DirectionClockwise))
+	assert.True(t, geometry.MillingConventional.Clockwise(false, gcode.DirectionClockwise))
+	assert.False(t, geometry.MillingClimb.Clockwise(true, gcode.DirectionCounterClockwise))
+}
+
+func TestPathWound(t *testing.T) {
+	t.Run("segments", func(t *testing.T) {
+		path := square(10)
+		assert.False(t, path.IsClockwise())
+
+		wound := path.Wound(true)
+		assert.True(t, wound.IsClockwise())
+		assert.InDelta(t, -100, wound.Area(), 1e-9)
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: 0}, *wound.Start())
+		assert.Equal(t, geometry.Coordinates{X: 0, Y: 10}, *wound[0].End())
+
+		assert.Equal(t, path, path.Wound(false))
+	})
+
+	t.Run("arcs", func(t *testing.T) {
+		path, err := square(10).Offset(1)
+		require.NoError(t, err)
+
+		wound := path.Wound(true)
+		assert.True(t, wound.IsClockwise())
+		assert.InDelta(t, -path.Area(), wound.Area(), 1e-9)
+	})
+
+	t.Run("open path", func(t *testing.T) {
+		path := geometry.Path{
+			&geometry.Segment{StartPoint: geometry.Coordinates{X: 0, Y: 0}, EndPoint: geometry.Coordinates{X: 10, Y: 0}},
+		}
+
+		assert.Equal(t, path, path.Wound(true))
+	})
+}
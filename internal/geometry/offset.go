@@ -0,0 +1,404 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	epsilon = 1e-9
+
+	// checkTolerance is the chord tolerance used to look for self intersections.
+	checkTolerance = 0.01
+)
+
+var (
+	// ErrOpenPath is returned when a closed path is expected.
+	ErrOpenPath = errors.New("path is not closed")
+
+	// ErrTooSmall is returned when a feature is too small for the tool.
+	ErrTooSmall = errors.New("feature too small for the tool")
+)
+
+// Compensate offsets the closed path by the tool radius on the requested side.
+func (p Path) Compensate(side Side, radius float64) (Path, error) {
+	switch side {
+	case SideInside:
+		return p.Offset(-radius)
+	case SideOutside:
+		return p.Offset(radius)
+	default:
+		return p, nil
+	}
+}
+
+// Offset grows the closed path by distance (shrinks it when distance is negative).
+// Outside corners are joined with arcs centered on the original corner, inside corners are trimmed.
+func (p Path) Offset(distance float64) (Path, error) {
+	elements := Path{}
+
+	for _, elt := range p.Unfold() {
+		if segment, ok := elt.(*Segment); ok && segment.Length() < epsilon {
+			continue
+		}
+
+		elements = append(elements, elt)
+	}
+
+	if !elements.IsClosed() {
+		return nil, ErrOpenPath
+	}
+
+	if distance == 0 {
+		return elements, nil
+	}
+
+	if elements.Area() > 0 {
+		return elements.offsetLeft(-distance)
+	}
+
+	return elements.offsetLeft(distance)
+}
+
+// offsetLeft shifts every element to its left by distance (to its right when distance is negative).
+// Curves collapsing under the shift are removed so that their neighbours meet on a sharp corner.
+func (p Path) offsetLeft(distance float64) (Path, error) {
+	originals := Path{}
+	shifted := Path{}
+
+	for _, elt := range p {
+		moved, err := shift(elt, distance)
+		if err != nil {
+			return nil, err
+		}
+
+		if moved == nil {
+			continue
+		}
+
+		originals = append(originals, elt)
+		shifted = append(shifted, moved)
+	}
+
+	if len(shifted) == 0 {
+		return nil, ErrTooSmall
+	}
+
+	joins := make(Path, len(shifted))
+
+	for idx := range shifted {
+		next := (idx + 1) % len(shifted)
+
+		corner, err := join(originals[idx], originals[next], shifted[idx], shifted[next], distance)
+		if err != nil {
+			return nil, err
+		}
+
+		joins[idx] = corner
+	}
+
+	output := Path{}
+
+	for idx, elt := range shifted {
+		if err := checkShifted(originals[idx], elt); err != nil {
+			return nil, err
+		}
+
+		if !elt.Start().Equal(*elt.End()) {
+			output = append(output, elt)
+		}
+
+		if joins[idx] != nil {
+			output = append(output, joins[idx])
+		}
+	}
+
+	if len(output) < 2 || output.selfIntersects(checkTolerance) {
+		return nil, ErrTooSmall
+	}
+
+	return output, nil
+}
+
+func shift(elt Linker, distance float64) (Linker, error) {
+	switch value := elt.(type) {
+	case *Segment:
+		normal := value.direction().leftNormal().scale(distance)
+
+		return &Segment{
+			Name:       value.Name,
+			StartPoint: value.StartPoint.add(normal),
+			EndPoint:   value.EndPoint.add(normal),
+		}, nil
+
+	case *Curve:
+		radius := value.Radius + distance
+		if value.counterClockwise() {
+			radius = value.Radius - distance
+		}
+
+		if radius < epsilon {
+			return nil, nil
+		}
+
+		return &Curve{
+			Name:       value.Name,
+			StartPoint: value.Center.add(value.StartPoint.sub(value.Center).unit().scale(radius)),
+			EndPoint:   value.Center.add(value.EndPoint.sub(value.Center).unit().scale(radius)),
+			Center:     value.Center,
+			Radius:     radius,
+			Clockwise:  value.Clockwise,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cannot offset %T", elt)
+}
+
+// join links two shifted elements. It returns the arc to add on outside corners,
+// and trims both elements on inside corners.
+func join(from Linker, to Linker, shiftedFrom Linker, shiftedTo Linker, distance float64) (Linker, error) {
+	endPoint := *shiftedFrom.End()
+	startPoint := *shiftedTo.Start()
+
+	if endPoint.DistanceTo(startPoint) < 1e-6 {
+		setStart(shiftedTo, endPoint)
+
+		return nil, nil
+	}
+
+	tangentFrom := endTangent(from)
+	tangentTo := startTangent(to)
+	turn := tangentFrom.cross(tangentTo)
+	corner := *from.End()
+
+	if math.Abs(turn) < epsilon && tangentFrom.dot(tangentTo) > 0 {
+		return &Segment{
+			Name:       linkerName(from) + " (joint)",
+			StartPoint: endPoint,
+			EndPoint:   startPoint,
+		}, nil
+	}
+
+	if turn*distance < 0 || math.Abs(turn) < epsilon {
+		counterClockwise := turn > 0
+		if math.Abs(turn) < epsilon {
+			counterClockwise = distance < 0
+		}
+
+		return &Curve{
+			Name:       linkerName(from) + " (corner)",
+			StartPoint: endPoint,
+			EndPoint:   startPoint,
+			Center:     corner,
+			Radius:     math.Abs(distance),
+			Clockwise:  counterClockwise,
+		}, nil
+	}
+
+	intersection, found := intersect(shiftedFrom, shiftedTo, corner)
+	if !found {
+		return nil, fmt.Errorf("%s: %w", linkerName(from), ErrTooSmall)
+	}
+
+	setEnd(shiftedFrom, intersection)
+	setStart(shiftedTo, intersection)
+
+	return nil, nil
+}
+
+// checkShifted detects elements reverted by the trimming of inside corners.
+func checkShifted(original Linker, shifted Linker) error {
+	if shifted.Start().Equal(*shifted.End()) {
+		return nil
+	}
+
+	switch value := shifted.(type) {
+	case *Segment:
+		if originalSegment, ok := original.(*Segment); ok && value.EndPoint.sub(value.StartPoint).dot(originalSegment.direction()) <= 0 {
+			return fmt.Errorf("%s: %w", value.Name, ErrTooSmall)
+		}
+	case *Curve:
+		if originalCurve, ok := original.(*Curve); ok && math.Abs(value.Sweep()) > math.Abs(originalCurve.Sweep())+1e-6 {
+			return fmt.Errorf("%s: %w", value.Name, ErrTooSmall)
+		}
+	}
+
+	return nil
+}
+
+func (p Path) selfIntersects(tolerance float64) bool {
+	points := p.Discretize(tolerance)
+
+	edges := [][2]Coordinates{}
+
+	for idx := 1; idx < len(points); idx++ {
+		if points[idx-1].DistanceTo(points[idx]) > epsilon {
+			edges = append(edges, [2]Coordinates{points[idx-1], points[idx]})
+		}
+	}
+
+	for first := range edges {
+		for second := first + 2; second < len(edges); second++ {
+			if first == 0 && second == len(edges)-1 {
+				continue
+			}
+
+			if segmentsCross(edges[first][0], edges[first][1], edges[second][0], edges[second][1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// segmentsCross checks whether two segments cross each other (touching ends excluded).
+func segmentsCross(startA Coordinates, endA Coordinates, startB Coordinates, endB Coordinates) bool {
+	directionA := endA.sub(startA)
+	directionB := endB.sub(startB)
+
+	side1 := directionA.cross(startB.sub(startA))
+	side2 := directionA.cross(endB.sub(startA))
+	side3 := directionB.cross(startA.sub(startB))
+	side4 := directionB.cross(endA.sub(startB))
+
+	return side1*side2 < -epsilon && side3*side4 < -epsilon
+}
+
+func startTangent(elt Linker) Coordinates {
+	switch value := elt.(type) {
+	case *Segment:
+		return value.direction()
+	case *Curve:
+		return value.tangentAt(value.StartPoint)
+	}
+
+	return Coordinates{}
+}
+
+func endTangent(elt Linker) Coordinates {
+	switch value := elt.(type) {
+	case *Segment:
+		return value.direction()
+	case *Curve:
+		return value.tangentAt(value.EndPoint)
+	}
+
+	return Coordinates{}
+}
+
+func setStart(elt Linker, point Coordinates) {
+	switch value := elt.(type) {
+	case *Segment:
+		value.StartPoint = point
+	case *Curve:
+		value.StartPoint = point
+	}
+}
+
+func setEnd(elt Linker, point Coordinates) {
+	switch value := elt.(type) {
+	case *Segment:
+		value.EndPoint = point
+	case *Curve:
+		value.EndPoint = point
+	}
+}
+
+func linkerName(elt Linker) string {
+	switch value := elt.(type) {
+	case *Segment:
+		return value.Name
+	case *Curve:
+		return value.Name
+	case Point:
+		return value.Name
+	}
+
+	return ""
+}
+
+// intersect computes the intersection of the supporting lines or circles
+// of two elements, the nearest to the reference point.
+func intersect(first Linker, second Linker, reference Coordinates) (Coordinates, bool) {
+	candidates := []Coordinates{}
+
+	firstSegment, firstIsSegment := first.(*Segment)
+	secondSegment, secondIsSegment := second.(*Segment)
+	firstCurve, firstIsCurve := first.(*Curve)
+	secondCurve, secondIsCurve := second.(*Curve)
+
+	switch {
+	case firstIsSegment && secondIsSegment:
+		candidates = lineLineIntersection(firstSegment.StartPoint, firstSegment.direction(), secondSegment.StartPoint, secondSegment.direction())
+	case firstIsSegment && secondIsCurve:
+		candidates = lineCircleIntersection(firstSegment.StartPoint, firstSegment.direction(), secondCurve.Center, secondCurve.Radius)
+	case firstIsCurve && secondIsSegment:
+		candidates = lineCircleIntersection(secondSegment.StartPoint, secondSegment.direction(), firstCurve.Center, firstCurve.Radius)
+	case firstIsCurve && secondIsCurve:
+		candidates = circleCircleIntersection(firstCurve.Center, firstCurve.Radius, secondCurve.Center, secondCurve.Radius)
+	}
+
+	if len(candidates) == 0 {
+		return Coordinates{}, false
+	}
+
+	output := candidates[0]
+
+	for _, candidate := range candidates[1:] {
+		if candidate.DistanceTo(reference) < output.DistanceTo(reference) {
+			output = candidate
+		}
+	}
+
+	return output, true
+}
+
+func lineLineIntersection(pointA Coordinates, directionA Coordinates, pointB Coordinates, directionB Coordinates) []Coordinates {
+	denominator := directionA.cross(directionB)
+	if math.Abs(denominator) < epsilon {
+		return nil
+	}
+
+	factor := pointB.sub(pointA).cross(directionB) / denominator
+
+	return []Coordinates{pointA.add(directionA.scale(factor))}
+}
+
+func lineCircleIntersection(point Coordinates, direction Coordinates, center Coordinates, radius float64) []Coordinates {
+	unitDirection := direction.unit()
+	fromCenter := point.sub(center)
+
+	halfB := fromCenter.dot(unitDirection)
+	discriminant := halfB*halfB - (fromCenter.dot(fromCenter) - radius*radius)
+
+	if discriminant < 0 {
+		return nil
+	}
+
+	root := math.Sqrt(discriminant)
+
+	return []Coordinates{
+		point.add(unitDirection.scale(-halfB + root)),
+		point.add(unitDirection.scale(-halfB - root)),
+	}
+}
+
+func circleCircleIntersection(centerA Coordinates, radiusA float64, centerB Coordinates, radiusB float64) []Coordinates {
+	distance := centerA.DistanceTo(centerB)
+	if distance < epsilon || distance > radiusA+radiusB || distance < math.Abs(radiusA-radiusB) {
+		return nil
+	}
+
+	along := (radiusA*radiusA - radiusB*radiusB + distance*distance) / (2 * distance)
+	height := math.Sqrt(math.Max(radiusA*radiusA-along*along, 0))
+
+	axis := centerB.sub(centerA).unit()
+	base := centerA.add(axis.scale(along))
+
+	return []Coordinates{
+		base.add(axis.leftNormal().scale(height)),
+		base.add(axis.leftNormal().scale(-height)),
+	}
+}
@@ -0,0 +1,89 @@
+package geometry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func square(size float64) geometry.Path {
+	return geometry.Path{
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: 0, Y: 0}, EndPoint: geometry.Coordinates{X: size, Y: 0}},
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: size, Y: 0}, EndPoint: geometry.Coordinates{X: size, Y: size}},
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: size, Y: size}, EndPoint: geometry.Coordinates{X: 0, Y: size}},
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: 0, Y: size}, EndPoint: geometry.Coordinates{X: 0, Y: 0}},
+	}
+}
+
+func TestPathOffset(t *testing.T) {
+	t.Run("outside square", func(t *testing.T) {
+		output, err := square(10).Compensate(geometry.SideOutside, 1)
+		require.NoError(t, err)
+
+		assert.Len(t, output, 8)
+		assert.True(t, output.IsClosed())
+		assert.InDelta(t, 100+40+math.Pi, output.Area(), 1e-6)
+		assert.Equal(t, geometry.Box{
+			Min: geometry.Coordinates{X: -1, Y: -1},
+			Max: geometry.Coordinates{X: 11, Y: 11},
+		}, output.Box())
+	})
+
+	t.Run("inside square", func(t *testing.T) {
+		output, err := square(10).Compensate(geometry.SideInside, 1)
+		require.NoError(t, err)
+
+		assert.Len(t, output, 4)
+		assert.True(t, output.IsClosed())
+		assert.InDelta(t, 64, output.Area(), 1e-6)
+	})
+
+	t.Run("inside clockwise square", func(t *testing.T) {
+		path := square(10)
+		path.Revert()
+
+		output, err := path.Compensate(geometry.SideInside, 1)
+		require.NoError(t, err)
+
+		assert.InDelta(t, -64, output.Area(), 1e-6)
+	})
+
+	t.Run("square too small", func(t *testing.T) {
+		_, err := square(10).Compensate(geometry.SideInside, 6)
+		assert.ErrorIs(t, err, geometry.ErrTooSmall)
+	})
+
+	t.Run("circle", func(t *testing.T) {
+		circle := geometry.Path{
+			&geometry.Curve{
+				StartPoint: geometry.Coordinates{X: 5, Y: 0},
+				EndPoint:   geometry.Coordinates{X: -5, Y: 0},
+				Radius:     5,
+			},
+			&geometry.Curve{
+				StartPoint: geometry.Coordinates{X: -5, Y: 0},
+				EndPoint:   geometry.Coordinates{X: 5, Y: 0},
+				Radius:     5,
+			},
+		}
+
+		inside, err := circle.Compensate(geometry.SideInside, 2)
+		require.NoError(t, err)
+		assert.InDelta(t, -9*math.Pi, inside.Area(), 1e-6)
+
+		outside, err := circle.Compensate(geometry.SideOutside, 2)
+		require.NoError(t, err)
+		assert.InDelta(t, -49*math.Pi, outside.Area(), 1e-6)
+
+		_, err = circle.Compensate(geometry.SideInside, 5)
+		assert.ErrorIs(t, err, geometry.ErrTooSmall)
+	})
+
+	t.Run("open path", func(t *testing.T) {
+		_, err := square(10)[:3].Offset(1)
+		assert.ErrorIs(t, err, geometry.ErrOpenPath)
+	})
+}
@@ -2,6 +2,7 @@ package geometry
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/landru29/cnc-drilling/internal/gcode"
 	"github.com/yofu/dxf/entity"
@@ -57,15 +58,40 @@ func (p Path) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 		config(&options)
 	}
 
+	if len(options.Spiral) > 0 {
+		if p.IsClosed() {
+			return p.marshallSpiral(options, configs, nil, nil)
+		}
+
+		if leadIn, loop, leadOut, ok := p.leads(); ok {
+			return loop.marshallSpiral(options, configs, leadIn, leadOut)
+		}
+	}
+
 	if !options.IgnoreStart {
 		start := p.Start()
-		output = fmt.Sprintf(
-			"G0 X%.03f Y%.03f\nG1 Z%.03f F%.03f; Tool down\n",
-			start.X-options.OffsetX(),
-			start.Y-options.OffsetY(),
-			-options.Deep,
-			options.Feed,
-		)
+		output = fmt.Sprintf("G0 X%.03f Y%.03f\n", start.X-options.OffsetX(), start.Y-options.OffsetY())
+
+		top := 0.0
+		if options.PreviousDeep > 0 {
+			top = -options.PreviousDeep
+		}
+
+		descents := p.Entry(options.Entry, top, -options.Deep, options.Slope())
+		if len(descents) == 0 {
+			output += options.Plunge(-options.Deep, "Tool down")
+		} else {
+			output += options.Plunge(top, "Tool down")
+		}
+
+		for _, descent := range descents {
+			out, err := gcode.Marshal(descent, configs...)
+			if err != nil {
+				return nil, err
+			}
+
+			output += string(out)
+		}
 	}
 
 	for _, segmentOrCurve := range p {
@@ -80,7 +106,7 @@ func (p Path) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 	}
 
 	if !options.IgnoreEnd {
-		output += fmt.Sprintf("G0 Z%.03f; Tool up\n", options.SecurityZ)
+		output += options.Retract("Tool up")
 	}
 
 	return []byte(output), nil
@@ -173,3 +199,104 @@ func buildPath(input []Linker) []Path {
 
 	return output
 }
+
+// Unfold returns the path with all nested paths expanded into segments and curves.
+func (p Path) Unfold() Path {
+	output := Path{}
+
+	for _, elt := range p {
+		switch value := elt.(type) {
+		case *Path:
+			output = append(output, value.Unfold()...)
+		case Path:
+			output = append(output, value.Unfold()...)
+		default:
+			output = append(output, elt)
+		}
+	}
+
+	return output
+}
+
+// IsClosed checks whether the path ends where it starts.
+func (p Path) IsClosed() bool {
+	start := p.Start()
+	end := p.End()
+
+	if start == nil || end == nil || len(p.Unfold()) < 2 {
+		return false
+	}
+
+	return start.Equal(*end)
+}
+
+// Length is the length of the path.
+func (p Path) Length() float64 {
+	output := 0.0
+
+	for _, elt := range p.Unfold() {
+		output += linkerLength(elt)
+	}
+
+	return output
+}
+
+// Area is the signed area enclosed by the path, positive when the path is counter-clockwise.
+// Curves contribute the area between their chord and their arc.
+func (p Path) Area() float64 {
+	output := 0.0
+
+	for _, elt := range p.Unfold() {
+		start := elt.Start()
+		end := elt.End()
+
+		output += start.cross(*end) / 2
+
+		if curve, ok := elt.(*Curve); ok {
+			sweep := curve.Sweep()
+			output += curve.Radius * curve.Radius * (sweep - math.Sin(sweep)) / 2
+		}
+	}
+
+	return output
+}
+
+// Discretize approximates the path with a polyline whose distance to curves stays under tolerance.
+func (p Path) Discretize(tolerance float64) []Coordinates {
+	start := p.Start()
+	if start == nil {
+		return nil
+	}
+
+	output := []Coordinates{*start}
+
+	for _, elt := range p.Unfold() {
+		curve, ok := elt.(*Curve)
+		if !ok {
+			output = append(output, *elt.End())
+
+			continue
+		}
+
+		sweep := curve.Sweep()
+
+		steps := 1
+		if tolerance > 0 && tolerance < curve.Radius {
+			steps = int(math.Ceil(math.Abs(sweep) / (2 * math.Acos(1-tolerance/curve.Radius))))
+		}
+
+		angleStart := math.Atan2(curve.StartPoint.Y-curve.Center.Y, curve.StartPoint.X-curve.Center.X)
+
+		for step := 1; step < steps; step++ {
+			angle := angleStart + sweep*float64(step)/float64(steps)
+			output = append(output, Coordinates{
+				X: curve.Center.X + curve.Radius*math.Cos(angle),
+				Y: curve.Center.Y + curve.Radius*math.Sin(angle),
+			})
+		}
+
+		output = append(output, curve.EndPoint)
+	}
+
+	return output
+}
@@ -3,6 +3,7 @@ package geometry
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/landru29/cnc-drilling/internal/gcode"
 	"github.com/yofu/dxf/entity"
@@ -37,6 +38,9 @@ func NewPointFromVertex(name string, data *entity.Vertex) Point {
 	}
 }
 
+// peckClearance is the distance kept above the previous peck when the tool goes back down.
+const peckClearance = 0.5
+
 // MarshallGCode implements the Marshaler interface.
 func (p Point) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 	options := gcode.Options{}
@@ -44,12 +48,61 @@ func (p Point) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 		config(&options)
 	}
 
-	return []byte(fmt.Sprintf(";------ Point %s\nG0 X%.03f Y%.03f\nG1 Z%.03f F%.03f; Tool down\nG0 Z%.03f; Tool up\n",
+	x := p.X - options.OffsetX()
+	y := p.Y - options.OffsetY()
+
+	peck := options.Peck
+	if peck <= 0 {
+		peck = options.Deep
+	}
+
+	switch options.Cycle {
+	case gcode.CycleG81:
+		return []byte(fmt.Sprintf(";------ Point %s\nG81 X%.03f Y%.03f Z%.03f R%.03f F%.03f\n",
+			p.Name,
+			x,
+			y,
+			-options.Deep,
+			options.RPlane(),
+			options.PlungeRate(),
+		)), nil
+
+	case gcode.CycleG83, gcode.CycleG73:
+		return []byte(fmt.Sprintf(";------ Point %s\n%s X%.03f Y%.03f Z%.03f R%.03f Q%.03f F%.03f\n",
+			p.Name,
+			strings.ToUpper(options.Cycle.String()),
+			x,
+			y,
+			-options.Deep,
+			options.RPlane(),
+			peck,
+			options.PlungeRate(),
+		)), nil
+
+	case gcode.CycleExpanded:
+		output := fmt.Sprintf(";------ Point %s\nG0 X%.03f Y%.03f\n", p.Name, x, y)
+
+		for deep := 0.0; deep < options.Deep; {
+			previous := deep
+			deep = math.Min(deep+peck, options.Deep)
+
+			if previous > 0 {
+				output += fmt.Sprintf("G0 Z%.03f\nG1 Z%.03f F%.03f; Peck\n", -previous+peckClearance, -deep, options.PlungeRate())
+			} else {
+				output += options.Plunge(-deep, "Peck")
+			}
+
+			output += options.Retract("Tool up")
+		}
+
+		return []byte(output), nil
+	}
+
+	return []byte(fmt.Sprintf(";------ Point %s\nG0 X%.03f Y%.03f\n%s%s",
 		p.Name,
-		p.X-options.OffsetX(),
-		p.Y-options.OffsetY(),
-		-options.Deep,
-		options.Feed,
-		options.SecurityZ,
+		x,
+		y,
+		options.Plunge(-options.Deep, "Tool down"),
+		options.Retract("Tool up"),
 	)), nil
 }
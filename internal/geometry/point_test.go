@@ -0,0 +1,65 @@
+package geometry_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointCycles(t *testing.T) {
+	point := geometry.Point{Name: "#0", Coordinates: geometry.Coordinates{X: 12, Y: 8}}
+
+	for name, testCase := range map[string]struct {
+		cycle    gcode.Cycle
+		peck     float64
+		expected string
+	}{
+		"g81": {
+			cycle:    gcode.CycleG81,
+			peck:     1,
+			expected: ";------ Point #0\nG81 X10.000 Y5.000 Z-2.500 R1.000 F50.000\n",
+		},
+		"g83": {
+			cycle:    gcode.CycleG83,
+			peck:     1,
+			expected: ";------ Point #0\nG83 X10.000 Y5.000 Z-2.500 R1.000 Q1.000 F50.000\n",
+		},
+		"g73": {
+			cycle:    gcode.CycleG73,
+			peck:     1,
+			expected: ";------ Point #0\nG73 X10.000 Y5.000 Z-2.500 R1.000 Q1.000 F50.000\n",
+		},
+		"g83 without peck": {
+			cycle:    gcode.CycleG83,
+			expected: ";------ Point #0\nG83 X10.000 Y5.000 Z-2.500 R1.000 Q2.500 F50.000\n",
+		},
+		"expanded": {
+			cycle: gcode.CycleExpanded,
+			peck:  1,
+			expected: ";------ Point #0\nG0 X10.000 Y5.000\n" +
+				"G0 Z1.000\nG1 Z-1.000 F50.000; Peck\nG0 Z5.000; Tool up\n" +
+				"G0 Z-0.500\nG1 Z-2.000 F50.000; Peck\nG0 Z5.000; Tool up\n" +
+				"G0 Z-1.500\nG1 Z-2.500 F50.000; Peck\nG0 Z5.000; Tool up\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, err := gcode.Marshal(
+				point,
+				gcode.WithDeep(2.5),
+				gcode.WithFeed(100),
+				gcode.WithSecurityZ(5),
+				gcode.WithClearance(1),
+				gcode.WithPlungeFeed(50),
+				gcode.WithOffset([]float64{2, 3}),
+				gcode.WithCycle(testCase.cycle),
+				gcode.WithPeck(testCase.peck),
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, string(code))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// Polygon is a closed polyline.
+type Polygon []Coordinates
+
+// Polygon approximates the closed path with a polygon.
+func (p Path) Polygon(tolerance float64) Polygon {
+	points := p.Discretize(tolerance)
+
+	if len(points) > 1 && points[0].Equal(points[len(points)-1]) {
+		points = points[:len(points)-1]
+	}
+
+	return Polygon(points)
+}
+
+func (p Polygon) edge(index int) (Coordinates, Coordinates) {
+	return p[index], p[(index+1)%len(p)]
+}
+
+// Contains checks whether the point is inside the polygon.
+func (p Polygon) Contains(point Coordinates) bool {
+	inside := false
+
+	for idx := range p {
+		start, end := p.edge(idx)
+
+		if (start.Y > point.Y) != (end.Y > point.Y) &&
+			point.X < (end.X-start.X)*(point.Y-start.Y)/(end.Y-start.Y)+start.X {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// DistanceTo computes the distance between the point and the polygon edges.
+func (p Polygon) DistanceTo(point Coordinates) float64 {
+	output := math.Inf(1)
+
+	for idx := range p {
+		start, end := p.edge(idx)
+		direction := end.sub(start)
+
+		position := 0.0
+		if length := direction.dot(direction); length > 0 {
+			position = math.Max(0, math.Min(1, point.sub(start).dot(direction)/length))
+		}
+
+		output = math.Min(output, point.DistanceTo(start.add(direction.scale(position))))
+	}
+
+	return output
+}
+
+// Intersections gives the positions (from 0 at start to 1 at end) where
+// the segment crosses the polygon edges.
+func (p Polygon) Intersections(start Coordinates, end Coordinates) []float64 {
+	output := []float64{}
+	direction := end.sub(start)
+
+	for idx := range p {
+		edgeStart, edgeEnd := p.edge(idx)
+		edgeDirection := edgeEnd.sub(edgeStart)
+
+		denominator := direction.cross(edgeDirection)
+		if math.Abs(denominator) < epsilon {
+			continue
+		}
+
+		position := edgeStart.sub(start).cross(edgeDirection) / denominator
+		edgePosition := edgeStart.sub(start).cross(direction) / denominator
+
+		if position > epsilon && position < 1-epsilon && edgePosition >= 0 && edgePosition < 1 {
+			output = append(output, position)
+		}
+	}
+
+	sort.Float64s(output)
+
+	return output
+}
+
+// Crosses checks whether the segment crosses the polygon edges.
+func (p Polygon) Crosses(start Coordinates, end Coordinates) bool {
+	return len(p.Intersections(start, end)) > 0
+}
+
+// ScanLine gives the abscissas where the horizontal line at ordinate y crosses the polygon.
+func (p Polygon) ScanLine(y float64) []float64 {
+	output := []float64{}
+
+	for idx := range p {
+		start, end := p.edge(idx)
+
+		if (start.Y > y) != (end.Y > y) {
+			output = append(output, (end.X-start.X)*(y-start.Y)/(end.Y-start.Y)+start.X)
+		}
+	}
+
+	sort.Float64s(output)
+
+	return output
+}
+
+// Box implements the Linker interface.
+func (p Polygon) Box() Box {
+	if len(p) == 0 {
+		return Box{}
+	}
+
+	output := p[0].Box()
+
+	for _, point := range p[1:] {
+		output = output.Merge(point.Box())
+	}
+
+	return output
+}
+
+// Path converts the polygon into a closed path of segments.
+func (p Polygon) Path(name string) Path {
+	output := make(Path, len(p))
+
+	for idx := range p {
+		start, end := p.edge(idx)
+
+		output[idx] = &Segment{
+			Name:       name,
+			StartPoint: start,
+			EndPoint:   end,
+		}
+	}
+
+	return output
+}
@@ -85,12 +85,11 @@ func (s Segment) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 	if !options.IgnoreStart {
 		start := s.Start()
 		output = fmt.Sprintf(
-			"; * %s\nG0 X%.03f Y%.03f\nG1 Z%.03f F%.03f; Tool down\n",
+			"; * %s\nG0 X%.03f Y%.03f\n%s",
 			s.Name,
 			start.X-options.OffsetX(),
 			start.Y-options.OffsetY(),
-			-options.Deep,
-			options.Feed,
+			options.Plunge(-options.Deep, "Tool down"),
 		)
 	}
 
@@ -103,3 +102,39 @@ func (s Segment) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
 
 	return []byte(output), nil
 }
+
+// Length is the length of the segment.
+func (s Segment) Length() float64 {
+	return s.StartPoint.DistanceTo(s.EndPoint)
+}
+
+func (s Segment) direction() Coordinates {
+	return s.EndPoint.sub(s.StartPoint).unit()
+}
+
+// Split cuts the segment at a position (from 0 at start to 1 at end).
+func (s Segment) Split(position float64) (*Segment, *Segment) {
+	middle := s.StartPoint.add(s.EndPoint.sub(s.StartPoint).scale(position))
+
+	return &Segment{
+		Name:       s.Name,
+		StartPoint: s.StartPoint,
+		EndPoint:   middle,
+	}, &Segment{
+		Name:       s.Name,
+		StartPoint: middle,
+		EndPoint:   s.EndPoint,
+	}
+}
+
+// project gives the position (from 0 at start to 1 at end) of the nearest point of the segment.
+func (s Segment) project(point Coordinates) float64 {
+	direction := s.EndPoint.sub(s.StartPoint)
+
+	length := direction.dot(direction)
+	if length == 0 {
+		return 0
+	}
+
+	return math.Max(0, math.Min(1, point.sub(s.StartPoint).dot(direction)/length))
+}
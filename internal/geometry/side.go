@@ -0,0 +1,62 @@
+package geometry
+
+import "fmt"
+
+// Side is the side of the path where the tool is driven.
+type Side int
+
+const (
+	// SideOn drives the tool center on the path.
+	SideOn Side = iota
+
+	// SideInside keeps the tool inside closed paths.
+	SideInside
+
+	// SideOutside keeps the tool outside closed paths.
+	SideOutside
+)
+
+// String implements the pflag.Value interface.
+func (s Side) String() string {
+	switch s {
+	case SideOn:
+		return "on"
+	case SideInside:
+		return "inside"
+	case SideOutside:
+		return "outside"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (s *Side) Set(value string) error {
+	switch value {
+	case "on", "":
+		*s = SideOn
+	case "inside":
+		*s = SideInside
+	case "outside":
+		*s = SideOutside
+	default:
+		return fmt.Errorf("unknown side: %s", value)
+	}
+
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (s Side) Type() string {
+	return "side"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s Side) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (s *Side) UnmarshalText(data []byte) error {
+	return s.Set(string(data))
+}
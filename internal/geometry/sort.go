@@ -91,6 +91,11 @@ func SortEntities(entities []Linker, from *Coordinates, filter func(from Linker,
 			continue
 
 		case from != nil:
+			if after == nil {
+				current = nil
+				continue
+			}
+
 			current = after
 
 			weight := end.Weight(current)
@@ -103,7 +108,7 @@ func SortEntities(entities []Linker, from *Coordinates, filter func(from Linker,
 
 			linkers = linkersAfter
 
-		case weightAfter > weightBefore || after == nil:
+		case after == nil || (before != nil && weightAfter > weightBefore):
 			current = before
 
 			weight := start.Weight(current)
@@ -116,7 +121,7 @@ func SortEntities(entities []Linker, from *Coordinates, filter func(from Linker,
 
 			linkers = linkersBefore
 
-		case weightAfter <= weightBefore || before == nil:
+		default:
 			current = after
 
 			weight := end.Weight(current)
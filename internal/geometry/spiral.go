@@ -0,0 +1,137 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+)
+
+// Spiral gives the moves going around the closed path, one turn for each height, the tool going down
+// continuously from the first height. The tool stays above the top of the tabs.
+func (p Path) Spiral(from float64, heights []float64) []Descent {
+	turn := p.Length()
+	if turn < epsilon {
+		return nil
+	}
+
+	output := []Descent{}
+
+	for idx, height := range heights {
+		start := from
+		if idx > 0 {
+			start = heights[idx-1]
+		}
+
+		position := 0.0
+
+		for _, elt := range p.Unfold() {
+			floor := math.Inf(-1)
+			elements := Path{elt}
+
+			if tab, ok := elt.(*Tab); ok {
+				floor = -tab.Top
+				elements = tab.Elements.flatten()
+			}
+
+			for _, element := range elements {
+				position += linkerLength(element)
+
+				output = append(output, Descent{
+					Linker: element,
+					Z:      math.Max(start-(start-height)*math.Min(1, position/turn), floor),
+				})
+			}
+		}
+	}
+
+	return output
+}
+
+// leads splits a closed path joined with leads into its lead-in, its closed loop, and its lead-out
+// (with the overlap). It fails when the path does not enter a closed loop.
+func (p Path) leads() (Path, Path, Path, bool) {
+	elements := p.Unfold()
+	if len(elements) < 3 || p.IsClosed() {
+		return nil, nil, nil, false
+	}
+
+	for idx := 2; idx < len(elements); idx++ {
+		if loop := elements[1 : idx+1]; loop.IsClosed() && loop.Start().Equal(*elements[0].End()) {
+			return elements[:1], loop, elements[idx+1:], true
+		}
+	}
+
+	return nil, nil, nil, false
+}
+
+// marshallSpiral cuts the closed path going down continuously to the deep, by the spiral deeps, and
+// finishes with a turn at the deep. The lead-in is cut at the top of the spiral, and the lead-out at
+// the deep.
+func (p Path) marshallSpiral(options gcode.Options, configs []gcode.Configurator, leadIn Path, leadOut Path) ([]byte, error) {
+	top := 0.0
+	if options.PreviousDeep > 0 {
+		top = -options.PreviousDeep
+	}
+
+	heights := make([]float64, len(options.Spiral))
+	for idx, deep := range options.Spiral {
+		heights[idx] = -deep
+	}
+
+	flat := append([]gcode.Configurator{}, configs...)
+	flat = append(flat, gcode.WithoutStart(), gcode.WithoutEnd(), gcode.WithSpiral(nil))
+
+	output := ""
+
+	if !options.IgnoreStart {
+		start := p.Start()
+		if len(leadIn) > 0 {
+			start = leadIn.Start()
+		}
+
+		output = fmt.Sprintf("G0 X%.03f Y%.03f\n%s", start.X-options.OffsetX(), start.Y-options.OffsetY(), options.Plunge(top, "Tool down"))
+	}
+
+	if len(leadIn) > 0 {
+		out, err := gcode.Marshal(leadIn, flat...)
+		if err != nil {
+			return nil, err
+		}
+
+		output += string(out)
+	}
+
+	height := top
+
+	for _, descent := range p.Spiral(top, heights) {
+		if descent.Z > height+epsilon {
+			output += fmt.Sprintf("G1 Z%.03f F%.03f; Tool up\n", descent.Z, options.Feed)
+		}
+
+		out, err := gcode.Marshal(descent, configs...)
+		if err != nil {
+			return nil, err
+		}
+
+		output += string(out)
+		height = descent.Z
+	}
+
+	if height > -options.Deep+epsilon {
+		output += fmt.Sprintf("G1 Z%.03f F%.03f; Tool down\n", -options.Deep, options.PlungeRate())
+	}
+
+	last, err := gcode.Marshal(append(append(Path{}, p...), leadOut...), flat...)
+	if err != nil {
+		return nil, err
+	}
+
+	output += string(last)
+
+	if !options.IgnoreEnd {
+		output += options.Retract("Tool up")
+	}
+
+	return []byte(output), nil
+}
@@ -0,0 +1,53 @@
+package geometry_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathSpiral(t *testing.T) {
+	t.Run("turns", func(t *testing.T) {
+		descents := square(10).Spiral(0, []float64{-1, -2})
+		require.Len(t, descents, 8)
+
+		for idx, descent := range descents {
+			assert.InDelta(t, -float64(idx+1)/4, descent.Z, 1e-9)
+		}
+	})
+
+	t.Run("over tabs", func(t *testing.T) {
+		path := square(10).WithTabs([]float64{15}, 2, 0.5)
+		lifted := 0
+
+		for _, descent := range path.Spiral(0, []float64{-4}) {
+			end := descent.Linker.End()
+			if end.X == 10 && end.Y > 4 && end.Y <= 6 {
+				assert.InDelta(t, -0.5, descent.Z, 1e-9)
+
+				lifted++
+			}
+		}
+
+		assert.NotZero(t, lifted)
+	})
+
+	t.Run("gcode", func(t *testing.T) {
+		code, err := gcode.Marshal(
+			square(10),
+			gcode.WithDeep(2),
+			gcode.WithFeed(100),
+			gcode.WithSecurityZ(5),
+			gcode.WithSpiral([]float64{1, 2}),
+		)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(string(code), "G0 X0.000 Y0.000\nG1 Z0.000 F100.000; Tool down\nG1 X10.000 Y0.000 Z-0.250 F100.000\n"))
+		assert.Contains(t, string(code), "G1 X0.000 Y0.000 Z-2.000 F100.000\n")
+		assert.True(t, strings.HasSuffix(string(code), "G1 X0.000 Y0.000 F100.000\nG0 Z5.000; Tool up\n"))
+		assert.Equal(t, 4, strings.Count(string(code), "Segment"))
+	})
+}
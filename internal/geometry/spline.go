@@ -0,0 +1,120 @@
+package geometry
+
+import (
+	"slices"
+)
+
+// NewPathFromSpline is a builder. The spline is a NURBS given by its degree, its knots, its control
+// points and their weights (all 1 when missing). A spline without control points goes straight
+// through its fit points.
+func NewPathFromSpline(
+	name string,
+	degree int,
+	knots []float64,
+	controls []Coordinates,
+	weights []float64,
+	fits []Coordinates,
+	tolerance float64,
+) *Path {
+	path := Path{}
+
+	if len(controls) == 0 {
+		for idx := 1; idx < len(fits); idx++ {
+			if !fits[idx-1].Equal(fits[idx]) {
+				path = append(path, &Segment{Name: name, StartPoint: fits[idx-1], EndPoint: fits[idx]})
+			}
+		}
+
+		return &path
+	}
+
+	degree = max(1, min(degree, len(controls)-1))
+
+	if len(weights) != len(controls) {
+		weights = make([]float64, len(controls))
+		for idx := range weights {
+			weights[idx] = 1
+		}
+	}
+
+	if len(knots) != len(controls)+degree+1 {
+		knots = clampedKnots(len(controls), degree)
+	}
+
+	spline := nurbs{degree: degree, knots: knots, controls: controls, weights: weights}
+
+	breaks := []float64{}
+
+	for _, knot := range knots[degree : len(knots)-degree] {
+		if len(breaks) == 0 || knot > breaks[len(breaks)-1] {
+			breaks = append(breaks, knot)
+		}
+	}
+
+	path = FitCurve(name, spline.at, breaks, tolerance)
+
+	return &path
+}
+
+// clampedKnots builds a uniform knot vector going through the first and the last control points.
+func clampedKnots(count int, degree int) []float64 {
+	output := make([]float64, count+degree+1)
+
+	for idx := range output {
+		output[idx] = float64(min(max(idx-degree, 0), count-degree))
+	}
+
+	return output
+}
+
+// nurbs is a non uniform rational B-spline.
+type nurbs struct {
+	degree   int
+	knots    []float64
+	controls []Coordinates
+	weights  []float64
+}
+
+// at evaluates the spline at a parameter with the de Boor algorithm.
+func (n nurbs) at(parameter float64) Coordinates {
+	last := len(n.controls) - 1
+
+	span, _ := slices.BinarySearch(n.knots, parameter)
+
+	// The span is the last knot interval [knots[span], knots[span+1]) holding the parameter.
+	for span < len(n.knots) && n.knots[span] <= parameter {
+		span++
+	}
+
+	span = min(max(span-1, n.degree), last)
+
+	points := make([]Coordinates, n.degree+1)
+	weights := make([]float64, n.degree+1)
+
+	for idx := range points {
+		weight := n.weights[span-n.degree+idx]
+		points[idx] = n.controls[span-n.degree+idx].scale(weight)
+		weights[idx] = weight
+	}
+
+	for level := 1; level <= n.degree; level++ {
+		for idx := n.degree; idx >= level; idx-- {
+			knot := span - n.degree + idx
+			denominator := n.knots[knot+n.degree-level+1] - n.knots[knot]
+
+			alpha := 0.0
+			if denominator != 0 {
+				alpha = (parameter - n.knots[knot]) / denominator
+			}
+
+			points[idx] = points[idx-1].scale(1 - alpha).add(points[idx].scale(alpha))
+			weights[idx] = weights[idx-1]*(1-alpha) + weights[idx]*alpha
+		}
+	}
+
+	if weights[n.degree] == 0 {
+		return points[n.degree]
+	}
+
+	return points[n.degree].scale(1 / weights[n.degree])
+}
@@ -0,0 +1,252 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+)
+
+// Tab is a part of a path where the tool lifts to leave material holding the part.
+type Tab struct {
+	Elements Path
+	Top      float64
+}
+
+// Start implements the Linker interface.
+func (t Tab) Start() *Coordinates {
+	return t.Elements.Start()
+}
+
+// End implements the Linker interface.
+func (t Tab) End() *Coordinates {
+	return t.Elements.End()
+}
+
+// Revert implements the Linker interface.
+func (t *Tab) Revert() {
+	t.Elements.Revert()
+}
+
+// Weight implements the Linker interface.
+func (t Tab) Weight(other Linker) [2]float64 {
+	return t.Elements.Weight(other)
+}
+
+// Box implements the Linker interface.
+func (t Tab) Box() Box {
+	return t.Elements.Box()
+}
+
+// MarshallGCode implements the Marshaler interface.
+// The tool is lifted to the top of the tab when the pass is deeper.
+func (t Tab) MarshallGCode(configs ...gcode.Configurator) ([]byte, error) {
+	options := gcode.Options{}
+	for _, config := range configs {
+		config(&options)
+	}
+
+	inner, err := gcode.Marshal(t.Elements, append([]gcode.Configurator{gcode.WithoutStart(), gcode.WithoutEnd()}, configs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Deep <= t.Top {
+		return inner, nil
+	}
+
+	return []byte(fmt.Sprintf(
+		";------ Tab\nG1 Z%.03f F%.03f; Tool up\n%sG1 Z%.03f F%.03f; Tool down\n",
+		-t.Top,
+		options.Feed,
+		string(inner),
+		-options.Deep,
+		options.PlungeRate(),
+	)), nil
+}
+
+// TabPositions spreads count tabs evenly along the path. Positions are distances from the path start.
+func (p Path) TabPositions(count int) []float64 {
+	length := p.Length()
+	output := make([]float64, count)
+
+	for idx := range output {
+		output[idx] = length * (float64(idx) + 0.5) / float64(count)
+	}
+
+	return output
+}
+
+// Project gives the position (distance from the path start) of the nearest point
+// of the path, and the distance to this point.
+func (p Path) Project(point Coordinates) (float64, float64) {
+	var (
+		position     float64
+		bestPosition float64
+		bestDistance = math.Inf(1)
+	)
+
+	for _, elt := range p.Unfold() {
+		var nearest Coordinates
+
+		length := linkerLength(elt)
+		fraction := 0.0
+
+		switch value := elt.(type) {
+		case *Segment:
+			fraction = value.project(point)
+			nearest = value.StartPoint.add(value.EndPoint.sub(value.StartPoint).scale(fraction))
+		case *Curve:
+			fraction = value.project(point)
+			nearest = value.pointAt(fraction)
+		default:
+			continue
+		}
+
+		if distance := point.DistanceTo(nearest); distance < bestDistance {
+			bestDistance = distance
+			bestPosition = position + fraction*length
+		}
+
+		position += length
+	}
+
+	return bestPosition, bestDistance
+}
+
+// WithTabs wraps the parts of the closed path covered by tabs. Tabs are centered on positions
+// (distances from the path start) and cover width. The path is rotated to start out of tabs.
+func (p Path) WithTabs(positions []float64, width float64, top float64) Path {
+	length := p.Length()
+	if length == 0 || len(positions) == 0 {
+		return p
+	}
+
+	ranges := [][2]float64{}
+
+	for _, position := range positions {
+		from := position - width/2
+		to := position + width/2
+
+		switch {
+		case width >= length:
+			ranges = append(ranges, [2]float64{0, length})
+		case from < 0:
+			ranges = append(ranges, [2]float64{from + length, length}, [2]float64{0, to})
+		case to > length:
+			ranges = append(ranges, [2]float64{from, length}, [2]float64{0, to - length})
+		default:
+			ranges = append(ranges, [2]float64{from, to})
+		}
+	}
+
+	cuts := []float64{}
+
+	for _, tabRange := range ranges {
+		cuts = append(cuts, tabRange[0], tabRange[1])
+	}
+
+	sort.Float64s(cuts)
+
+	inTab := func(position float64) bool {
+		for _, tabRange := range ranges {
+			if position >= tabRange[0] && position <= tabRange[1] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	output := Path{}
+	position := 0.0
+
+	var tab *Tab
+
+	for _, elt := range p.splitAt(cuts) {
+		length := linkerLength(elt)
+		middle := position + length/2
+		position += length
+
+		if !inTab(middle) {
+			tab = nil
+			output = append(output, elt)
+
+			continue
+		}
+
+		if tab == nil {
+			tab = &Tab{Top: top}
+			output = append(output, tab)
+		}
+
+		tab.Elements = append(tab.Elements, elt)
+	}
+
+	for idx, elt := range output {
+		if _, isTab := elt.(*Tab); !isTab {
+			return append(output[idx:], output[:idx]...)
+		}
+	}
+
+	return output
+}
+
+// splitAt cuts the elements of the path at positions (distances from the path start, sorted).
+func (p Path) splitAt(positions []float64) Path {
+	output := Path{}
+	position := 0.0
+	cutIndex := 0
+
+	for _, elt := range p.Unfold() {
+		length := linkerLength(elt)
+		end := position + length
+		start := position
+		current := elt
+
+		for cutIndex < len(positions) && positions[cutIndex] < end-epsilon {
+			if positions[cutIndex] <= start+epsilon {
+				cutIndex++
+
+				continue
+			}
+
+			fraction := (positions[cutIndex] - start) / (end - start)
+
+			switch value := current.(type) {
+			case *Segment:
+				first, second := value.Split(fraction)
+				output = append(output, first)
+				current = second
+			case *Curve:
+				first, second := value.Split(fraction)
+				output = append(output, first)
+				current = second
+			}
+
+			start = positions[cutIndex]
+			cutIndex++
+		}
+
+		output = append(output, current)
+		position = end
+	}
+
+	return output
+}
+
+func linkerLength(elt Linker) float64 {
+	switch value := elt.(type) {
+	case *Segment:
+		return value.Length()
+	case *Curve:
+		return value.Length()
+	case *Tab:
+		return value.Elements.Length()
+	case *Path:
+		return value.Length()
+	}
+
+	return 0
+}
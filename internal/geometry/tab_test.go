@@ -0,0 +1,65 @@
+package geometry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathWithTabs(t *testing.T) {
+	t.Run("evenly spread", func(t *testing.T) {
+		output := square(10).WithTabs(square(10).TabPositions(2), 2, 1)
+
+		tabs := []*geometry.Tab{}
+
+		for _, elt := range output {
+			if tab, ok := elt.(*geometry.Tab); ok {
+				tabs = append(tabs, tab)
+			}
+		}
+
+		assert.Len(t, tabs, 2)
+		assert.InDelta(t, 40, output.Length(), 1e-9)
+		assert.True(t, output.IsClosed())
+
+		for _, tab := range tabs {
+			assert.InDelta(t, 2, tab.Elements.Length(), 1e-9)
+			assert.InDelta(t, 1, tab.Top, 1e-9)
+		}
+	})
+
+	t.Run("on the path start", func(t *testing.T) {
+		output := square(10).WithTabs([]float64{0}, 2, 1)
+
+		_, startsWithTab := output[0].(*geometry.Tab)
+		assert.False(t, startsWithTab)
+		assert.InDelta(t, 40, output.Length(), 1e-9)
+		assert.True(t, output.IsClosed())
+	})
+
+	t.Run("in the middle of an arc", func(t *testing.T) {
+		circle := geometry.Path{
+			&geometry.Curve{
+				StartPoint: geometry.Coordinates{X: 5, Y: 0},
+				EndPoint:   geometry.Coordinates{X: -5, Y: 0},
+				Radius:     5,
+			},
+			&geometry.Curve{
+				StartPoint: geometry.Coordinates{X: -5, Y: 0},
+				EndPoint:   geometry.Coordinates{X: 5, Y: 0},
+				Radius:     5,
+			},
+		}
+
+		position, distance := circle.Project(geometry.Coordinates{X: 0, Y: -8})
+		assert.InDelta(t, 5*math.Pi/2, position, 1e-9)
+		assert.InDelta(t, 3, distance, 1e-9)
+
+		output := circle.WithTabs([]float64{position}, 1, 0.5)
+
+		assert.Len(t, output, 4)
+		assert.InDelta(t, 10*math.Pi, output.Length(), 1e-9)
+	})
+}
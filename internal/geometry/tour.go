@@ -0,0 +1,200 @@
+package geometry
+
+import (
+	"time"
+)
+
+const (
+	// maxOrOptLength is the longest chain of stops moved at once by the Or-opt improvement.
+	maxOrOptLength = 3
+
+	// maxRounds is the count of improvement rounds, so that the tour does not depend on the speed
+	// of the computer.
+	maxRounds = 100
+)
+
+// TourLength computes the travel distance from the start through all the entities, in order.
+func TourLength[T Linker](from Coordinates, entities []T) float64 {
+	output := 0.0
+	position := from
+
+	for _, entity := range entities {
+		start := entity.Start()
+		if start == nil {
+			continue
+		}
+
+		output += position.DistanceTo(*start)
+
+		if end := entity.End(); end != nil {
+			position = *end
+		}
+	}
+
+	return output
+}
+
+// OptimizeTour orders the entities to shorten the travel from the start. The tour is seeded with
+// the nearest neighbour, then improved with 2-opt and Or-opt moves until no move improves it, for
+// at most maxRounds rounds. The budget only caps the time spent on large tours. Entities are taken
+// as stops: only their start is considered.
+func OptimizeTour[T Linker](from Coordinates, entities []T, budget time.Duration) []T {
+	if len(entities) < 3 {
+		return entities
+	}
+
+	deadline := time.Now().Add(budget)
+
+	stops := make([]Coordinates, len(entities))
+	for idx, entity := range entities {
+		if start := entity.Start(); start != nil {
+			stops[idx] = *start
+		}
+	}
+
+	tour := &tour{stops: stops, order: nearestNeighbour(from, stops)}
+
+	for round := 0; round < maxRounds && time.Now().Before(deadline); round++ {
+		if !tour.twoOpt(from, deadline) && !tour.orOpt(from, deadline) {
+			break
+		}
+	}
+
+	output := make([]T, len(entities))
+	for idx, stop := range tour.order {
+		output[idx] = entities[stop]
+	}
+
+	return output
+}
+
+// tour is an open tour through stops, starting at a fixed position.
+type tour struct {
+	stops []Coordinates
+	order []int
+}
+
+// at gives the position of the i-th stop of the tour. The position 0 is the start.
+func (t tour) at(from Coordinates, position int) Coordinates {
+	if position == 0 {
+		return from
+	}
+
+	return t.stops[t.order[position-1]]
+}
+
+// distance computes the distance between two positions of the tour. Going past the end costs nothing.
+func (t tour) distance(from Coordinates, first int, second int) float64 {
+	if first > len(t.order) || second > len(t.order) {
+		return 0
+	}
+
+	return t.at(from, first).DistanceTo(t.at(from, second))
+}
+
+// twoOpt reverses the parts of the tour where it shortens the travel.
+func (t *tour) twoOpt(from Coordinates, deadline time.Time) bool {
+	improved := false
+	size := len(t.order)
+
+	for first := 1; first < size; first++ {
+		if time.Now().After(deadline) {
+			return improved
+		}
+
+		for last := first + 1; last <= size; last++ {
+			delta := t.distance(from, first-1, last) + t.distance(from, first, last+1) -
+				t.distance(from, first-1, first) - t.distance(from, last, last+1)
+
+			if delta < -epsilon {
+				for left, right := first-1, last-1; left < right; left, right = left+1, right-1 {
+					t.order[left], t.order[right] = t.order[right], t.order[left]
+				}
+
+				improved = true
+			}
+		}
+	}
+
+	return improved
+}
+
+// orOpt moves short chains of stops, possibly reversed, where it shortens the travel.
+func (t *tour) orOpt(from Coordinates, deadline time.Time) bool {
+	improved := false
+	size := len(t.order)
+
+	for length := 1; length <= maxOrOptLength && length < size; length++ {
+		for first := 1; first+length-1 <= size; first++ {
+			if time.Now().After(deadline) {
+				return improved
+			}
+
+			last := first + length - 1
+			removal := t.distance(from, first-1, first) + t.distance(from, last, last+1) - t.distance(from, first-1, last+1)
+
+			for position := 0; position <= size; position++ {
+				if position >= first-1 && position <= last {
+					continue
+				}
+
+				next := position + 1
+
+				edge := t.distance(from, position, next)
+				forward := t.distance(from, position, first) + t.distance(from, last, next) - edge
+				backward := t.distance(from, position, last) + t.distance(from, first, next) - edge
+
+				if forward < removal-epsilon || backward < removal-epsilon {
+					t.move(first, last, position, backward < forward)
+
+					improved = true
+
+					break
+				}
+			}
+		}
+	}
+
+	return improved
+}
+
+// move puts the chain of stops between first and last after the position.
+func (t *tour) move(first int, last int, position int, reverse bool) {
+	chain := append([]int{}, t.order[first-1:last]...)
+	if reverse {
+		for left, right := 0, len(chain)-1; left < right; left, right = left+1, right-1 {
+			chain[left], chain[right] = chain[right], chain[left]
+		}
+	}
+
+	rest := append(append([]int{}, t.order[:first-1]...), t.order[last:]...)
+
+	if position > last {
+		position -= len(chain)
+	}
+
+	t.order = append(append(append([]int{}, rest[:position]...), chain...), rest[position:]...)
+}
+
+// nearestNeighbour builds a tour going each time to the closest stop not visited yet.
+func nearestNeighbour(from Coordinates, stops []Coordinates) []int {
+	visited := make([]bool, len(stops))
+	output := make([]int, 0, len(stops))
+	position := from
+
+	for range stops {
+		best := -1
+
+		for idx, stop := range stops {
+			if !visited[idx] && (best < 0 || position.DistanceTo(stop) < position.DistanceTo(stops[best])) {
+				best = idx
+			}
+		}
+
+		visited[best] = true
+		output = append(output, best)
+		position = stops[best]
+	}
+
+	return output
+}
@@ -0,0 +1,55 @@
+package geometry_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimizeTour(t *testing.T) {
+	points := []geometry.Point{}
+
+	for x := 1; x <= 10; x++ {
+		for y := 1; y <= 10; y++ {
+			points = append(points, geometry.Point{Coordinates: geometry.Coordinates{X: float64(x * 10), Y: float64(y * 10)}})
+		}
+	}
+
+	rand.New(rand.NewSource(1)).Shuffle(len(points), func(i, j int) {
+		points[i], points[j] = points[j], points[i]
+	})
+
+	output := geometry.OptimizeTour(geometry.Coordinates{}, points, time.Second)
+
+	assert.ElementsMatch(t, points, output)
+	assert.Less(t, geometry.TourLength(geometry.Coordinates{}, output), geometry.TourLength(geometry.Coordinates{}, points))
+	assert.InDelta(t, 990+10*1.4142, geometry.TourLength(geometry.Coordinates{}, output), 50)
+}
+
+func TestOptimizeTourStart(t *testing.T) {
+	points := []geometry.Point{}
+
+	for x := 0; x <= 10; x++ {
+		points = append(points, geometry.Point{Coordinates: geometry.Coordinates{X: float64((x * 7) % 11 * 10)}})
+	}
+
+	output := geometry.OptimizeTour(geometry.Coordinates{X: 100}, points, time.Second)
+
+	assert.Equal(t, geometry.Coordinates{X: 100}, output[0].Coordinates)
+	assert.Equal(t, geometry.Coordinates{X: 0}, output[len(output)-1].Coordinates)
+	assert.InDelta(t, 100, geometry.TourLength(geometry.Coordinates{X: 100}, output), 1e-9)
+}
+
+func TestOptimizeTourReproducible(t *testing.T) {
+	points := []geometry.Point{}
+
+	random := rand.New(rand.NewSource(2))
+	for range 200 {
+		points = append(points, geometry.Point{Coordinates: geometry.Coordinates{X: random.Float64() * 100, Y: random.Float64() * 100}})
+	}
+
+	assert.Equal(t, geometry.OptimizeTour(geometry.Coordinates{}, points, time.Minute), geometry.OptimizeTour(geometry.Coordinates{}, points, 2*time.Minute))
+}
@@ -0,0 +1,108 @@
+package geometry
+
+import "math"
+
+// Contour is a closed path with the closed paths nested inside it.
+type Contour struct {
+	Index    int // index of the path in the list the tree is built from
+	Path     Path
+	Children []*Contour
+}
+
+// ContainmentTree nests the closed paths: each closed path is a child of the smallest closed path
+// containing it. Open paths are left out. Curves are approximated within the tolerance.
+func ContainmentTree(paths []Path, tolerance float64) []*Contour {
+	contours := []*Contour{}
+
+	for idx, path := range paths {
+		if path.IsClosed() {
+			contours = append(contours, &Contour{Index: idx, Path: path})
+		}
+	}
+
+	polygons := make([]Polygon, len(contours))
+	for idx, contour := range contours {
+		polygons[idx] = contour.Path.Unfold().Polygon(tolerance)
+	}
+
+	output := []*Contour{}
+
+	for idx, contour := range contours {
+		parent := -1
+
+		for other := range contours {
+			if other == idx || !polygons[other].Contains(*contour.Path.Start()) {
+				continue
+			}
+
+			if parent < 0 || math.Abs(contours[other].Path.Area()) < math.Abs(contours[parent].Path.Area()) {
+				parent = other
+			}
+		}
+
+		if parent < 0 {
+			output = append(output, contour)
+
+			continue
+		}
+
+		contours[parent].Children = append(contours[parent].Children, contour)
+	}
+
+	return output
+}
+
+// InnerFirst orders the paths so that the closed paths come after the closed paths nested inside
+// them. The order is kept otherwise.
+func InnerFirst(paths []Path, tolerance float64) []Path {
+	output := make([]Path, 0, len(paths))
+	roots := map[int]*Contour{}
+	nested := map[int]bool{}
+
+	for _, contour := range ContainmentTree(paths, tolerance) {
+		roots[contour.Index] = contour
+
+		contour.Walk(func(current *Contour, _ int) {
+			nested[current.Index] = true
+		})
+	}
+
+	for idx, path := range paths {
+		if contour, ok := roots[idx]; ok {
+			output = append(output, contour.innerFirst()...)
+
+			continue
+		}
+
+		if !nested[idx] {
+			output = append(output, path)
+		}
+	}
+
+	return output
+}
+
+// Walk calls fn on the contour and on the contours nested inside it, parents first, with their level
+// of nesting.
+func (c *Contour) Walk(fn func(contour *Contour, level int)) {
+	c.walk(fn, 0)
+}
+
+func (c *Contour) walk(fn func(contour *Contour, level int), level int) {
+	fn(c, level)
+
+	for _, child := range c.Children {
+		child.walk(fn, level+1)
+	}
+}
+
+// innerFirst gives the paths of the contour, children first.
+func (c *Contour) innerFirst() []Path {
+	output := []Path{}
+
+	for _, child := range c.Children {
+		output = append(output, child.innerFirst()...)
+	}
+
+	return append(output, c.Path)
+}
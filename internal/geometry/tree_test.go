@@ -0,0 +1,66 @@
+package geometry_test
+
+import (
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func squareAt(x float64, y float64, size float64) geometry.Path {
+	output := square(size)
+
+	for _, elt := range output {
+		segment, _ := elt.(*geometry.Segment)
+		segment.StartPoint = geometry.Coordinates{X: segment.StartPoint.X + x, Y: segment.StartPoint.Y + y}
+		segment.EndPoint = geometry.Coordinates{X: segment.EndPoint.X + x, Y: segment.EndPoint.Y + y}
+	}
+
+	return output
+}
+
+func TestContainmentTree(t *testing.T) {
+	paths := []geometry.Path{
+		squareAt(0, 0, 100),
+		squareAt(10, 10, 20),
+		squareAt(200, 0, 10),
+		squareAt(12, 12, 5),
+		{
+			&geometry.Segment{StartPoint: geometry.Coordinates{X: 50, Y: 50}, EndPoint: geometry.Coordinates{X: 60, Y: 60}},
+		},
+	}
+
+	tree := geometry.ContainmentTree(paths, 0.01)
+	require.Len(t, tree, 2)
+
+	assert.Equal(t, 0, tree[0].Index)
+	require.Len(t, tree[0].Children, 1)
+	assert.Equal(t, 1, tree[0].Children[0].Index)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, 3, tree[0].Children[0].Children[0].Index)
+	assert.Equal(t, 2, tree[1].Index)
+	assert.Empty(t, tree[1].Children)
+
+	levels := map[int]int{}
+
+	tree[0].Walk(func(contour *geometry.Contour, level int) {
+		levels[contour.Index] = level
+	})
+
+	assert.Equal(t, map[int]int{0: 0, 1: 1, 3: 2}, levels)
+}
+
+func TestInnerFirst(t *testing.T) {
+	outer := squareAt(0, 0, 100)
+	middle := squareAt(10, 10, 20)
+	inner := squareAt(12, 12, 5)
+	other := squareAt(200, 0, 10)
+	open := geometry.Path{
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: 50, Y: 50}, EndPoint: geometry.Coordinates{X: 60, Y: 60}},
+	}
+
+	output := geometry.InnerFirst([]geometry.Path{outer, open, middle, other, inner}, 0.01)
+
+	assert.Equal(t, []geometry.Path{inner, middle, outer, open, other}, output)
+}
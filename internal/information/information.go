@@ -3,13 +3,16 @@ package information
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/drawing"
 	"github.com/landru29/cnc-drilling/internal/geometry"
-	"github.com/yofu/dxf"
-	"github.com/yofu/dxf/entity"
 )
 
+// tolerance is the chord tolerance used to approximate curves.
+const tolerance = 0.01
+
 type counters struct {
 	points         int
 	lines          int
@@ -18,34 +21,32 @@ type counters struct {
 	polylines      int
 	lightpolylines int
 	vertices       int
+	paths          int
+	ellipses       int
+	splines        int
+	textStrokes    int
 }
 
 // Process is the information reader process.
 func Process(in io.Reader, out io.Writer, config configuration.Config) error {
-	drawing, err := dxf.FromReader(in)
+	input, err := drawing.FromReader(in)
 	if err != nil {
 		return err
 	}
 
-	defer func(closer io.Closer) {
-		_ = closer.Close()
-	}(drawing)
-
-	if _, err := fmt.Fprintf(out, "%d layer(s) found:\n", len(drawing.Layers)); err != nil {
+	if _, err := fmt.Fprintf(out, "%d layer(s) found:\n", len(input.Layers)); err != nil {
 		return err
 	}
 
 	layers := config.Layers
 	if len(layers) == 0 {
-		for _, layer := range drawing.Layers {
-			layers = append(layers, layer.Name())
-		}
+		layers = append(layers, input.Layers...)
 	}
 
 	for _, layer := range layers {
 		isDefault := ""
 
-		if drawing.CurrentLayer.Name() == layer {
+		if input.CurrentLayer == layer {
 			isDefault = " [default]"
 		}
 
@@ -53,35 +54,56 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 			return err
 		}
 
+		if diameter, ok := input.Tools[layer]; ok {
+			if _, err := fmt.Fprintf(out, "\t\tTool: D%.03f\n", diameter); err != nil {
+				return err
+			}
+		}
+
 		var (
 			box           *geometry.Box
 			entityCounter counters
+			points        []geometry.Linker
+			linkers       []geometry.Linker
 		)
 
-		for idx, dxfEntity := range geometry.FilterEntities(drawing.Entities(), layer) {
-			switch dxfEntity.(type) {
-			case *entity.Point:
+		for _, element := range input.Filter(layer) {
+			switch element.Kind {
+			case drawing.KindPoint:
 				entityCounter.points++
-			case *entity.Vertex:
+				points = append(points, element.Linker)
+			case drawing.KindVertex:
 				entityCounter.vertices++
-			case *entity.Line:
+			case drawing.KindLine:
 				entityCounter.lines++
-			case *entity.Arc:
+				linkers = append(linkers, element.Linker)
+			case drawing.KindArc:
 				entityCounter.ars++
-			case *entity.Circle:
+				linkers = append(linkers, element.Linker)
+			case drawing.KindCircle:
 				entityCounter.circles++
-			case *entity.Polyline:
+				linkers = append(linkers, element.Linker)
+			case drawing.KindPolyline:
 				entityCounter.polylines++
-			case *entity.LwPolyline:
+				linkers = append(linkers, element.Linker)
+			case drawing.KindLightPolyline:
 				entityCounter.lightpolylines++
+				linkers = append(linkers, element.Linker)
+			case drawing.KindPath:
+				entityCounter.paths++
+				linkers = append(linkers, element.Linker)
+			case drawing.KindEllipse:
+				entityCounter.ellipses++
+				linkers = append(linkers, element.Linker)
+			case drawing.KindSpline:
+				entityCounter.splines++
+				linkers = append(linkers, element.Linker)
+			case drawing.KindText:
+				entityCounter.textStrokes++
+				linkers = append(linkers, element.Linker)
 			}
 
-			data := geometry.NewLinker(fmt.Sprintf("#%d", idx), dxfEntity)
-			if data == nil {
-				continue
-			}
-
-			currentBox := data.Box()
+			currentBox := element.Linker.Box()
 
 			if box == nil {
 				box = &currentBox
@@ -99,6 +121,20 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 			}
 		}
 
+		if len(points) > 1 {
+			sorted := geometry.PointsFromLinkers(points...)
+			optimized := geometry.OptimizeTour(geometry.Coordinates{}, sorted, config.OptimizeTime)
+
+			if _, err := fmt.Fprintf(
+				out,
+				"\t\tRapids: %.01f mm (optimized: %.01f mm)\n",
+				geometry.TourLength(geometry.Coordinates{}, sorted),
+				geometry.TourLength(geometry.Coordinates{}, optimized),
+			); err != nil {
+				return err
+			}
+		}
+
 		if entityCounter.lines != 0 {
 			if _, err := fmt.Fprintf(out, "\t\tLines: %d\n", entityCounter.lines); err != nil {
 				return err
@@ -135,6 +171,34 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 			}
 		}
 
+		if entityCounter.paths != 0 {
+			if _, err := fmt.Fprintf(out, "\t\tPaths: %d\n", entityCounter.paths); err != nil {
+				return err
+			}
+		}
+
+		if entityCounter.ellipses != 0 {
+			if _, err := fmt.Fprintf(out, "\t\tEllipses: %d\n", entityCounter.ellipses); err != nil {
+				return err
+			}
+		}
+
+		if entityCounter.splines != 0 {
+			if _, err := fmt.Fprintf(out, "\t\tSplines: %d\n", entityCounter.splines); err != nil {
+				return err
+			}
+		}
+
+		if entityCounter.textStrokes != 0 {
+			if _, err := fmt.Fprintf(out, "\t\tText strokes: %d\n", entityCounter.textStrokes); err != nil {
+				return err
+			}
+		}
+
+		if err := writeContours(out, geometry.ContainmentTree(geometry.PathsFromLinkers(linkers...), tolerance)); err != nil {
+			return err
+		}
+
 		if box != nil {
 			if _, err := fmt.Fprintf(out, "\t\tBox %s\n", box); err != nil {
 				return err
@@ -144,3 +208,39 @@ func Process(in io.Reader, out io.Writer, config configuration.Config) error {
 
 	return nil
 }
+
+// writeContours writes the tree of the closed paths, the nested ones being indented below the
+// path containing them.
+func writeContours(out io.Writer, contours []*geometry.Contour) error {
+	if len(contours) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(out, "\t\tContours:\n"); err != nil {
+		return err
+	}
+
+	var err error
+
+	for _, contour := range contours {
+		contour.Walk(func(current *geometry.Contour, level int) {
+			if err != nil {
+				return
+			}
+
+			_, err = fmt.Fprintf(
+				out,
+				"\t\t\t%s- #%d %s\n",
+				strings.Repeat("  ", level),
+				current.Index,
+				current.Path.Box(),
+			)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package information_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/information"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessBaloon(t *testing.T) {
+	source, err := os.ReadFile("../../testdata/baloon.dxf")
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+
+	require.NoError(t, information.Process(bytes.NewReader(source), out, configuration.Config{}))
+
+	assert.Contains(t, out.String(), "15 layer(s) found:\n")
+	assert.Contains(t, out.String(), "\t* bloc1\n\t\tArcs: 3\n\t\tContours:\n\t\t\t- #0 [(-70.000, -5.000), (-19.417, 67.253)]\n")
+}
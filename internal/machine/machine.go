@@ -9,12 +9,13 @@ import (
 	"github.com/landru29/cnc-drilling/internal/geometry"
 )
 
-// Path is a gcode path.
+// Path is a gcode path. The rapid moves are counted in the duration when the rapid feed is set.
 type Path struct {
 	CurrentPosition geometry.Coordinates
 	CurrentZ        float64
 	Distance        float64
 	Duration        time.Duration
+	RapidFeed       float64
 }
 
 // NewPath is a builder.
@@ -27,6 +28,10 @@ func NewPath(x float64, y float64, z float64) *Path {
 }
 
 func duration(distance float64, feed float64) time.Duration {
+	if feed <= 0 {
+		return 0
+	}
+
 	return time.Duration((distance * float64(time.Minute)) / feed)
 }
 
@@ -61,6 +66,7 @@ func (p *Path) MoveToXY(x float64, y float64, feed float64, out io.Writer) error
 	return nil
 }
 
+// MoveToZ moves to a Z position.
 func (p *Path) MoveToZ(z float64, feed float64, out io.Writer) error {
 	distance := math.Abs(p.CurrentZ - z)
 	p.Distance += distance
@@ -81,18 +87,17 @@ func (p *Path) ArcTo(x float64, y float64, centerX float64, centerY float64, clo
 		code = 3
 	}
 
-	angleStart := math.Atan2(p.CurrentPosition.Y-centerY, p.CurrentPosition.X-centerX)
-	angleEnd := math.Atan2(y-centerY, x-centerX)
-	radius := math.Sqrt((p.CurrentPosition.X-centerX)*(p.CurrentPosition.X-centerX) + (p.CurrentPosition.Y-centerY)*(p.CurrentPosition.Y-centerY))
+	arcLength := geometry.Curve{
+		StartPoint: p.CurrentPosition,
+		EndPoint:   geometry.Coordinates{X: x, Y: y},
+		Center:     geometry.Coordinates{X: centerX, Y: centerY},
+		Radius:     p.CurrentPosition.DistanceTo(geometry.Coordinates{X: centerX, Y: centerY}),
+		Clockwise:  clockwise,
+	}.Length()
 
-	angleDiff := angleEnd - angleStart
-	if clockwise && angleDiff > 0 {
-		angleDiff -= 2 * math.Pi
-	} else if !clockwise && angleDiff < 0 {
-		angleDiff += 2 * math.Pi
-	}
+	centerOffsetX := centerX - p.CurrentPosition.X
+	centerOffsetY := centerY - p.CurrentPosition.Y
 
-	arcLength := math.Abs(angleDiff * radius)
 	p.Distance += arcLength
 	p.CurrentPosition.X = x
 	p.CurrentPosition.Y = y
@@ -104,8 +109,8 @@ func (p *Path) ArcTo(x float64, y float64, centerX float64, centerY float64, clo
 		code,
 		x,
 		y,
-		centerX-p.CurrentPosition.X,
-		centerY-p.CurrentPosition.Y,
+		centerOffsetX,
+		centerOffsetY,
 		feed,
 	); err != nil {
 		return err
@@ -114,18 +119,122 @@ func (p *Path) ArcTo(x float64, y float64, centerX float64, centerY float64, clo
 	return nil
 }
 
-// Retract moves the tool to the security Z height.
-func (p *Path) Retract(securityZ float64, feed float64, out io.Writer) error {
-	if p.CurrentZ < securityZ {
-		distance := securityZ - p.CurrentZ
-		p.Distance += distance
-		p.CurrentZ = securityZ
-		p.Duration += duration(distance, feed)
+// HelixTo creates an arc to a position, going down (or up) to z on the way.
+func (p *Path) HelixTo(x float64, y float64, z float64, centerX float64, centerY float64, clockwise bool, feed float64, out io.Writer) error {
+	code := 2
+	if clockwise {
+		code = 3
+	}
+
+	arcLength := geometry.Curve{
+		StartPoint: p.CurrentPosition,
+		EndPoint:   geometry.Coordinates{X: x, Y: y},
+		Center:     geometry.Coordinates{X: centerX, Y: centerY},
+		Radius:     p.CurrentPosition.DistanceTo(geometry.Coordinates{X: centerX, Y: centerY}),
+		Clockwise:  clockwise,
+	}.Length()
+
+	centerOffsetX := centerX - p.CurrentPosition.X
+	centerOffsetY := centerY - p.CurrentPosition.Y
+	distance := math.Hypot(arcLength, p.CurrentZ-z)
+
+	p.Distance += distance
+	p.CurrentPosition.X = x
+	p.CurrentPosition.Y = y
+	p.CurrentZ = z
+	p.Duration += duration(distance, feed)
+
+	if _, err := fmt.Fprintf(
+		out,
+		"G%d X%.03f Y%.03f Z%.03f I%.03f J%.03f F%.03f\n",
+		code,
+		x,
+		y,
+		z,
+		centerOffsetX,
+		centerOffsetY,
+		feed,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RapidToXY moves to a XY position at rapid speed.
+func (p *Path) RapidToXY(x float64, y float64, out io.Writer) error {
+	distance := p.CurrentPosition.DistanceTo(geometry.Coordinates{X: x, Y: y})
+	p.Distance += distance
+	p.CurrentPosition.X = x
+	p.CurrentPosition.Y = y
+	p.Duration += duration(distance, p.RapidFeed)
+
+	if _, err := fmt.Fprintf(out, "G0 X%.3f Y%.3f\n", x, y); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RapidTo moves to a position at rapid speed.
+func (p *Path) RapidTo(x float64, y float64, z float64, out io.Writer) error {
+	distance := math.Sqrt((p.CurrentZ-z)*(p.CurrentZ-z) + (p.CurrentPosition.X-x)*(p.CurrentPosition.X-x) + (p.CurrentPosition.Y-y)*(p.CurrentPosition.Y-y))
+	p.Distance += distance
+	p.CurrentPosition.X = x
+	p.CurrentPosition.Y = y
+	p.CurrentZ = z
+	p.Duration += duration(distance, p.RapidFeed)
 
-		if _, err := fmt.Fprintf(out, "G1 Z%.3f F%.0f\n", securityZ, feed); err != nil {
+	if _, err := fmt.Fprintf(out, "G0 X%.3f Y%.3f Z%.3f\n", x, y, z); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RapidToZ moves to a Z position at rapid speed.
+func (p *Path) RapidToZ(z float64, out io.Writer) error {
+	distance := math.Abs(p.CurrentZ - z)
+	p.Distance += distance
+	p.CurrentZ = z
+	p.Duration += duration(distance, p.RapidFeed)
+
+	if _, err := fmt.Fprintf(out, "G0 Z%.3f\n", z); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Plunge moves the tool down: at rapid speed to the clearance plane when set and above z, then at the feed.
+func (p *Path) Plunge(z float64, clearance float64, feed float64, out io.Writer) error {
+	if clearance > 0 && clearance > z && clearance < p.CurrentZ {
+		if err := p.RapidToZ(clearance, out); err != nil {
 			return err
 		}
 	}
 
+	return p.MoveToZ(z, feed, out)
+}
+
+// Retract moves the tool to the security Z height: at rapid speed, or at the feed when set.
+func (p *Path) Retract(securityZ float64, feed float64, out io.Writer) error {
+	if p.CurrentZ >= securityZ {
+		return nil
+	}
+
+	if feed <= 0 {
+		return p.RapidToZ(securityZ, out)
+	}
+
+	distance := securityZ - p.CurrentZ
+	p.Distance += distance
+	p.CurrentZ = securityZ
+	p.Duration += duration(distance, feed)
+
+	if _, err := fmt.Fprintf(out, "G1 Z%.3f F%.0f\n", securityZ, feed); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,44 @@
+package pocketer
+
+import "fmt"
+
+// Method is the pocket clearing method.
+type Method int
+
+const (
+	// MethodContour clears the pocket with passes parallel to its contour.
+	MethodContour Method = iota
+
+	// MethodZigzag clears the pocket with a raster of parallel lines.
+	MethodZigzag
+)
+
+// String implements the pflag.Value interface.
+func (m Method) String() string {
+	switch m {
+	case MethodContour:
+		return "contour"
+	case MethodZigzag:
+		return "zigzag"
+	default:
+		return "unknown"
+	}
+}
+
+// Set implements the pflag.Value interface.
+func (m *Method) Set(value string) error {
+	switch value {
+	case "contour":
+		*m = MethodContour
+	case "zigzag":
+		*m = MethodZigzag
+	default:
+		return fmt.Errorf("unknown pocket method: %s", value)
+	}
+	return nil
+}
+
+// Type implements the pflag.Value interface.
+func (m Method) Type() string {
+	return "pocketMethod"
+}
@@ -0,0 +1,229 @@
+package pocketer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/drawing"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/machine"
+)
+
+// Process is the pocketing process.
+func Process(in io.Reader, out io.Writer, config configuration.Config, step float64, method Method) error {
+	if config.ToolDiameter <= 0 {
+		return errors.New("tool diameter is required to clear pockets")
+	}
+
+	if step <= 0 {
+		step = config.ToolDiameter / 2
+	}
+
+	input, err := drawing.FromReader(in)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(out, config.Post.Preamble(config.SecurityZ)); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "%s\n", config.BeforeScript); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(out, config.Spindle().Start()); err != nil {
+		return err
+	}
+
+	linkers := []geometry.Linker{}
+
+	var shapeBox *geometry.Box
+
+	for _, element := range input.Filter(config.Layers...) {
+		switch element.Kind {
+		case drawing.KindLine, drawing.KindArc, drawing.KindCircle, drawing.KindPolyline, drawing.KindLightPolyline, drawing.KindPath,
+			drawing.KindEllipse, drawing.KindSpline:
+			linkers = append(linkers, element.Linker)
+		}
+
+		currentBox := element.Linker.Box()
+
+		if shapeBox == nil {
+			shapeBox = &currentBox
+
+			continue
+		}
+
+		currentBox = currentBox.Merge(*shapeBox)
+		shapeBox = &currentBox
+	}
+
+	radius := config.ToolDiameter / 2
+	offset := config.Origin.Computed(shapeBox)
+	tryDeeps := config.TryDeeps()
+	path := machine.NewPath(0, 0, config.SecurityZ)
+	path.RapidFeed = config.RapidFeed
+
+	for idx, pocket := range regions(geometry.PathsFromLinkers(linkers...)) {
+		passes := pocket.passes(method, radius, step)
+		if len(passes) == 0 {
+			return fmt.Errorf("pocket #%d: %w", idx, geometry.ErrTooSmall)
+		}
+
+		linkable := pocket.linkable(radius)
+		previous := config.DeepStart
+
+		for deepIndex, deep := range tryDeeps {
+			if _, err := fmt.Fprintf(
+				out,
+				";\n;=== Pocket #%d %d/%d ===\n",
+				idx,
+				deepIndex+1,
+				len(tryDeeps),
+			); err != nil {
+				return err
+			}
+
+			if err := machinePasses(passes, linkable, path, out, config, previous, deep, offset); err != nil {
+				return err
+			}
+
+			previous = deep
+		}
+	}
+
+	if err := path.Retract(config.SecurityZ, config.RetractFeed, out); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(out, config.Spindle().Stop()); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(out, "%s\n", config.AfterScript); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// machinePasses machines the passes at one deep, going down from the previous one. The tool stays down
+// between two passes when linkable allows it.
+func machinePasses(
+	passes []geometry.Path,
+	linkable func(from geometry.Coordinates, to geometry.Coordinates) bool,
+	path *machine.Path,
+	out io.Writer,
+	config configuration.Config,
+	previous float64,
+	deep float64,
+	offset []float64,
+) error {
+	var position *geometry.Coordinates
+
+	offsetX := offset[0]
+	offsetY := offset[1]
+
+	for _, pass := range passes {
+		start := pass.Start()
+		if start == nil {
+			continue
+		}
+
+		if position != nil && linkable(*position, *start) {
+			if err := path.MoveToXY(start.X-offsetX, start.Y-offsetY, config.Feed, out); err != nil {
+				return err
+			}
+		} else {
+			if err := path.Retract(config.SecurityZ, config.RetractFeed, out); err != nil {
+				return err
+			}
+
+			if err := path.RapidToXY(start.X-offsetX, start.Y-offsetY, out); err != nil {
+				return err
+			}
+
+			if err := entry(pass, path, out, config, previous, deep, offset); err != nil {
+				return err
+			}
+		}
+
+		for _, elt := range pass.Unfold() {
+			switch value := elt.(type) {
+			case *geometry.Segment:
+				if err := path.MoveToXY(value.EndPoint.X-offsetX, value.EndPoint.Y-offsetY, config.Feed, out); err != nil {
+					return err
+				}
+			case *geometry.Curve:
+				if err := path.ArcTo(
+					value.EndPoint.X-offsetX,
+					value.EndPoint.Y-offsetY,
+					value.Center.X-offsetX,
+					value.Center.Y-offsetY,
+					value.Clockwise,
+					config.Feed,
+					out,
+				); err != nil {
+					return err
+				}
+			}
+		}
+
+		position = pass.End()
+	}
+
+	return path.Retract(config.SecurityZ, config.RetractFeed, out)
+}
+
+// entry takes the tool down to the deep at the start of the pass: straight down, or from the previous
+// deep along the pass.
+func entry(
+	pass geometry.Path,
+	path *machine.Path,
+	out io.Writer,
+	config configuration.Config,
+	previous float64,
+	deep float64,
+	offset []float64,
+) error {
+	top := 0.0
+	if previous > 0 {
+		top = -previous
+	}
+
+	descents := pass.Entry(config.Entry, top, -deep, config.Slope())
+	if len(descents) == 0 {
+		return path.Plunge(-deep, config.Clearance, config.PlungeRate(), out)
+	}
+
+	if err := path.Plunge(top, config.Clearance, config.PlungeRate(), out); err != nil {
+		return err
+	}
+
+	for _, descent := range descents {
+		switch value := descent.Linker.(type) {
+		case *geometry.Segment:
+			if err := path.MoveTo(value.EndPoint.X-offset[0], value.EndPoint.Y-offset[1], descent.Z, config.Feed, out); err != nil {
+				return err
+			}
+		case *geometry.Curve:
+			if err := path.HelixTo(
+				value.EndPoint.X-offset[0],
+				value.EndPoint.Y-offset[1],
+				descent.Z,
+				value.Center.X-offset[0],
+				value.Center.Y-offset[1],
+				value.Clockwise,
+				config.Feed,
+				out,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
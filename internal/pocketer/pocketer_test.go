@@ -0,0 +1,67 @@
+package pocketer_test
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/pocketer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yofu/dxf"
+)
+
+func TestProcessIsland(t *testing.T) {
+	drawing := dxf.NewDrawing()
+
+	_, err := drawing.LwPolyline(true, []float64{0, 0, 0}, []float64{60, 0, 0}, []float64{60, 40, 0}, []float64{0, 40, 0}, []float64{0, 0, 0})
+	require.NoError(t, err)
+
+	_, err = drawing.Circle(30, 20, 0, 8)
+	require.NoError(t, err)
+
+	source := bytes.NewBuffer(nil)
+	_, err = drawing.WriteTo(source)
+	require.NoError(t, err)
+
+	moveRegexp := regexp.MustCompile(`^G1 X([-\d.]+) Y([-\d.]+)`)
+
+	for _, method := range []pocketer.Method{pocketer.MethodContour, pocketer.MethodZigzag} {
+		t.Run(method.String(), func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+
+			require.NoError(t, pocketer.Process(
+				bytes.NewReader(source.Bytes()),
+				out,
+				configuration.Config{Feed: 100, SecurityZ: 5, Deepness: 1, ToolDiameter: 3},
+				2,
+				method,
+			))
+
+			moves := 0
+
+			for _, line := range bytes.Split(out.Bytes(), []byte("\n")) {
+				matches := moveRegexp.FindSubmatch(line)
+				if matches == nil {
+					continue
+				}
+
+				x, err := strconv.ParseFloat(string(matches[1]), 64)
+				require.NoError(t, err)
+
+				y, err := strconv.ParseFloat(string(matches[2]), 64)
+				require.NoError(t, err)
+
+				moves++
+
+				assert.GreaterOrEqual(t, math.Hypot(x-30, y-20), 9.5-0.01, "tool cuts the island at (%f, %f)", x, y)
+				assert.True(t, x >= 1.5-0.01 && x <= 58.5+0.01 && y >= 1.5-0.01 && y <= 38.5+0.01, "tool cuts the wall at (%f, %f)", x, y)
+			}
+
+			assert.NotZero(t, moves)
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package pocketer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+const (
+	// tolerance is the chord tolerance used to approximate curves.
+	tolerance = 0.01
+
+	// maxLevels limits the number of contour parallel passes.
+	maxLevels = 10000
+)
+
+// region is a pocket boundary with the islands to leave standing.
+type region struct {
+	boundary geometry.Path
+	islands  []geometry.Path
+}
+
+// regions sorts the closed paths into pockets and islands. A closed path nested
+// in an odd number of closed paths is an island of its direct container.
+func regions(paths []geometry.Path) []region {
+	indexes := []int{}
+	byIndex := map[int]region{}
+
+	for _, root := range geometry.ContainmentTree(paths, tolerance) {
+		root.Walk(func(contour *geometry.Contour, level int) {
+			if level%2 == 1 {
+				return
+			}
+
+			current := region{boundary: contour.Path.Unfold()}
+
+			for _, child := range contour.Children {
+				current.islands = append(current.islands, child.Path.Unfold())
+			}
+
+			indexes = append(indexes, contour.Index)
+			byIndex[contour.Index] = current
+		})
+	}
+
+	sort.Ints(indexes)
+
+	output := make([]region, len(indexes))
+	for idx, index := range indexes {
+		output[idx] = byIndex[index]
+	}
+
+	return output
+}
+
+// walls computes the limits of the tool center at a distance from the walls of the region.
+func (r region) walls(distance float64) (geometry.Path, []geometry.Path, error) {
+	boundary, err := r.boundary.Offset(-distance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !boundary.IsClosed() {
+		return nil, nil, geometry.ErrTooSmall
+	}
+
+	islands := make([]geometry.Path, len(r.islands))
+
+	for idx, island := range r.islands {
+		grown, err := island.Offset(distance)
+		if err != nil {
+			// Islands too complex to be offset are protected by their grown bounding box.
+			box := island.Box()
+			grown = geometry.Polygon{
+				{X: box.Min.X - distance, Y: box.Min.Y - distance},
+				{X: box.Max.X + distance, Y: box.Min.Y - distance},
+				{X: box.Max.X + distance, Y: box.Max.Y + distance},
+				{X: box.Min.X - distance, Y: box.Max.Y + distance},
+			}.Path(fmt.Sprintf("island #%d", idx))
+		}
+
+		islands[idx] = grown
+	}
+
+	return boundary, islands, nil
+}
+
+// rings computes the passes at a distance from the walls of the region.
+func (r region) rings(distance float64) []geometry.Path {
+	boundary, islands, err := r.walls(distance)
+	if err != nil {
+		return nil
+	}
+
+	boundaryPolygon := boundary.Polygon(tolerance)
+
+	islandPolygons := make([]geometry.Polygon, len(islands))
+	for idx, island := range islands {
+		islandPolygons[idx] = island.Polygon(tolerance)
+	}
+
+	output := clipPath(boundary, islandPolygons, func(point geometry.Coordinates) bool {
+		return !containedBy(point, islandPolygons, -1)
+	})
+
+	for idx, island := range islands {
+		cutters := []geometry.Polygon{boundaryPolygon}
+
+		for other, polygon := range islandPolygons {
+			if other != idx {
+				cutters = append(cutters, polygon)
+			}
+		}
+
+		output = append(output, clipPath(island, cutters, func(point geometry.Coordinates) bool {
+			return boundaryPolygon.Contains(point) && !containedBy(point, islandPolygons, idx)
+		})...)
+	}
+
+	return output
+}
+
+// contour computes the contour parallel passes, from the center to the walls.
+func (r region) contour(radius float64, step float64) []geometry.Path {
+	levels := [][]geometry.Path{}
+
+	for level := range maxLevels {
+		rings := r.rings(radius + float64(level)*step)
+		if len(rings) == 0 {
+			break
+		}
+
+		levels = append(levels, rings)
+	}
+
+	output := []geometry.Path{}
+
+	for idx := len(levels) - 1; idx >= 0; idx-- {
+		output = append(output, levels[idx]...)
+	}
+
+	return output
+}
+
+// zigzag computes the raster passes followed by a finishing pass along the walls.
+func (r region) zigzag(radius float64, step float64) []geometry.Path {
+	boundary, islands, err := r.walls(radius)
+	if err != nil {
+		return nil
+	}
+
+	polygons := []geometry.Polygon{boundary.Polygon(tolerance)}
+	for _, island := range islands {
+		polygons = append(polygons, island.Polygon(tolerance))
+	}
+
+	box := polygons[0].Box()
+
+	lines := int(math.Ceil(box.Height() / step))
+	if lines < 1 {
+		lines = 1
+	}
+
+	spacing := box.Height() / float64(lines)
+
+	output := []geometry.Path{}
+	forward := true
+
+	for line := range lines {
+		y := box.Min.Y + (float64(line)+0.5)*spacing
+
+		abscissas := []float64{}
+		for _, polygon := range polygons {
+			abscissas = append(abscissas, polygon.ScanLine(y)...)
+		}
+
+		sort.Float64s(abscissas)
+
+		intervals := [][2]float64{}
+
+		for idx := 1; idx < len(abscissas); idx++ {
+			middle := geometry.Coordinates{X: (abscissas[idx-1] + abscissas[idx]) / 2, Y: y}
+
+			if !insideWalls(middle, polygons) {
+				continue
+			}
+
+			if len(intervals) > 0 && intervals[len(intervals)-1][1] == abscissas[idx-1] {
+				intervals[len(intervals)-1][1] = abscissas[idx]
+
+				continue
+			}
+
+			intervals = append(intervals, [2]float64{abscissas[idx-1], abscissas[idx]})
+		}
+
+		if !forward {
+			for left, right := 0, len(intervals)-1; left < right; left, right = left+1, right-1 {
+				intervals[left], intervals[right] = intervals[right], intervals[left]
+			}
+		}
+
+		for _, interval := range intervals {
+			segment := &geometry.Segment{
+				Name:       fmt.Sprintf("line #%d", line),
+				StartPoint: geometry.Coordinates{X: interval[0], Y: y},
+				EndPoint:   geometry.Coordinates{X: interval[1], Y: y},
+			}
+
+			if !forward {
+				segment.Revert()
+			}
+
+			output = append(output, geometry.Path{segment})
+		}
+
+		forward = !forward
+	}
+
+	return append(output, r.rings(radius)...)
+}
+
+// passes computes the passes of the region with the selected method.
+func (r region) passes(method Method, radius float64, step float64) []geometry.Path {
+	switch method {
+	case MethodZigzag:
+		return r.zigzag(radius, step)
+	default:
+		return r.contour(radius, step)
+	}
+}
+
+// linkable checks whether the tool can go straight from a pass to the next one without retracting.
+func (r region) linkable(radius float64) func(from geometry.Coordinates, to geometry.Coordinates) bool {
+	boundary, islands, err := r.walls(radius)
+	if err != nil {
+		return func(geometry.Coordinates, geometry.Coordinates) bool { return false }
+	}
+
+	polygons := []geometry.Polygon{boundary.Polygon(tolerance)}
+	for _, island := range islands {
+		polygons = append(polygons, island.Polygon(tolerance))
+	}
+
+	return func(from geometry.Coordinates, to geometry.Coordinates) bool {
+		if from.DistanceTo(to) > 2*radius {
+			return false
+		}
+
+		for _, polygon := range polygons {
+			if polygon.Crosses(from, to) {
+				return false
+			}
+		}
+
+		middle := geometry.Coordinates{X: (from.X + to.X) / 2, Y: (from.Y + to.Y) / 2}
+
+		for _, polygon := range polygons {
+			if polygon.DistanceTo(middle) < tolerance {
+				return true
+			}
+		}
+
+		return insideWalls(middle, polygons)
+	}
+}
+
+// insideWalls checks whether the point is inside the first polygon and outside the others.
+func insideWalls(point geometry.Coordinates, polygons []geometry.Polygon) bool {
+	return polygons[0].Contains(point) && !containedBy(point, polygons[1:], -1)
+}
+
+func containedBy(point geometry.Coordinates, polygons []geometry.Polygon, except int) bool {
+	for idx, polygon := range polygons {
+		if idx != except && polygon.Contains(point) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clipPath splits the path where it crosses the cutters, and keeps the pieces selected by keep.
+func clipPath(path geometry.Path, cutters []geometry.Polygon, keep func(geometry.Coordinates) bool) []geometry.Path {
+	points := path.Discretize(tolerance)
+	if len(points) < 2 {
+		return nil
+	}
+
+	crossing := false
+
+	for idx := 1; idx < len(points) && !crossing; idx++ {
+		for _, cutter := range cutters {
+			if cutter.Crosses(points[idx-1], points[idx]) {
+				crossing = true
+
+				break
+			}
+		}
+	}
+
+	if !crossing {
+		if keep(points[0]) {
+			return []geometry.Path{path}
+		}
+
+		return nil
+	}
+
+	pieces := [][]geometry.Coordinates{}
+	current := []geometry.Coordinates{}
+
+	for idx := 1; idx < len(points); idx++ {
+		start := points[idx-1]
+		end := points[idx]
+
+		positions := []float64{0, 1}
+		for _, cutter := range cutters {
+			positions = append(positions, cutter.Intersections(start, end)...)
+		}
+
+		sort.Float64s(positions)
+
+		for position := 1; position < len(positions); position++ {
+			from := interpolate(start, end, positions[position-1])
+			to := interpolate(start, end, positions[position])
+
+			if !keep(interpolate(start, end, (positions[position-1]+positions[position])/2)) {
+				if len(current) > 1 {
+					pieces = append(pieces, current)
+				}
+
+				current = []geometry.Coordinates{}
+
+				continue
+			}
+
+			if len(current) == 0 {
+				current = append(current, from)
+			}
+
+			current = append(current, to)
+		}
+	}
+
+	if len(current) > 1 {
+		pieces = append(pieces, current)
+	}
+
+	if len(pieces) > 1 && pieces[0][0].Equal(points[0]) && pieces[len(pieces)-1][len(pieces[len(pieces)-1])-1].Equal(points[len(points)-1]) {
+		merged := append(pieces[len(pieces)-1], pieces[0][1:]...)
+		pieces = append([][]geometry.Coordinates{merged}, pieces[1:len(pieces)-1]...)
+	}
+
+	output := make([]geometry.Path, len(pieces))
+
+	for idx, piece := range pieces {
+		clipped := geometry.Path{}
+
+		for pointIndex := 1; pointIndex < len(piece); pointIndex++ {
+			clipped = append(clipped, &geometry.Segment{
+				Name:       fmt.Sprintf("piece #%d", idx),
+				StartPoint: piece[pointIndex-1],
+				EndPoint:   piece[pointIndex],
+			})
+		}
+
+		output[idx] = clipped
+	}
+
+	return output
+}
+
+func interpolate(start geometry.Coordinates, end geometry.Coordinates, position float64) geometry.Coordinates {
+	return geometry.Coordinates{
+		X: start.X + (end.X-start.X)*position,
+		Y: start.Y + (end.Y-start.Y)*position,
+	}
+}
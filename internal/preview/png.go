@@ -0,0 +1,123 @@
+package preview
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// colors are the colors of the kinds of strokes in the PNG.
+var colors = map[kind]color.RGBA{
+	kindSource: {R: 0x4c, G: 0xaf, B: 0x50, A: 0xff},
+	kindBox:    {R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+	kindRapid:  {R: 0xff, G: 0x98, B: 0x00, A: 0xff},
+	kindCut:    {R: 0x15, G: 0x65, B: 0xc0, A: 0xff},
+	kindPlunge: {R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff},
+}
+
+// canvas is an image the scene is drawn on.
+type canvas struct {
+	image  *image.RGBA
+	bounds geometry.Box
+	scale  float64
+}
+
+// WritePNG writes the scene as a PNG image, size pixels wide or high.
+func (s Scene) WritePNG(out io.Writer, size int) error {
+	bounds := s.bounds()
+	scale := float64(size) / math.Max(bounds.Width(), bounds.Height())
+
+	target := canvas{
+		image: image.NewRGBA(image.Rect(
+			0,
+			0,
+			max(1, int(math.Ceil(bounds.Width()*scale))),
+			max(1, int(math.Ceil(bounds.Height()*scale))),
+		)),
+		bounds: bounds,
+		scale:  scale,
+	}
+
+	for idx := range target.image.Pix {
+		target.image.Pix[idx] = 0xff
+	}
+
+	for _, current := range s.strokes {
+		if len(current.points) == 1 {
+			target.disc(current.points[0], 3, colors[current.kind])
+
+			continue
+		}
+
+		dash := 0
+		switch current.kind {
+		case kindRapid:
+			dash = 4
+		case kindBox:
+			dash = 8
+		}
+
+		width := 1
+		if current.kind == kindCut {
+			width = 2
+		}
+
+		for idx := 1; idx < len(current.points); idx++ {
+			target.line(current.points[idx-1], current.points[idx], width, dash, colors[current.kind])
+		}
+	}
+
+	return png.Encode(out, target.image)
+}
+
+// pixel gives the position of a point in the image, the Y axis going down.
+func (c canvas) pixel(point geometry.Coordinates) (float64, float64) {
+	return (point.X - c.bounds.Min.X) * c.scale, (c.bounds.Max.Y - point.Y) * c.scale
+}
+
+// line draws a line, dashed every dash pixels when dash is set.
+func (c canvas) line(from geometry.Coordinates, to geometry.Coordinates, width int, dash int, paint color.RGBA) {
+	fromX, fromY := c.pixel(from)
+	toX, toY := c.pixel(to)
+
+	steps := int(math.Ceil(math.Max(math.Abs(toX-fromX), math.Abs(toY-fromY))))
+
+	for step := 0; step <= steps; step++ {
+		if dash > 0 && (step/dash)%2 == 1 {
+			continue
+		}
+
+		fraction := 0.0
+		if steps > 0 {
+			fraction = float64(step) / float64(steps)
+		}
+
+		c.dot(int(math.Round(fromX+(toX-fromX)*fraction)), int(math.Round(fromY+(toY-fromY)*fraction)), width, paint)
+	}
+}
+
+// disc draws a disc of a radius in pixels.
+func (c canvas) disc(center geometry.Coordinates, radius int, paint color.RGBA) {
+	centerX, centerY := c.pixel(center)
+
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				c.image.SetRGBA(int(math.Round(centerX))+x, int(math.Round(centerY))+y, paint)
+			}
+		}
+	}
+}
+
+// dot draws a square dot of a width in pixels.
+func (c canvas) dot(x int, y int, width int, paint color.RGBA) {
+	for dy := 0; dy < width; dy++ {
+		for dx := 0; dx < width; dx++ {
+			c.image.SetRGBA(x+dx-width/2, y+dy-width/2, paint)
+		}
+	}
+}
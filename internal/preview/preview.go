@@ -0,0 +1,172 @@
+package preview
+
+import (
+	"io"
+	"math"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/drawing"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+)
+
+// tolerance is the chord tolerance used to draw curves.
+const tolerance = 0.05
+
+// kind is the kind of a stroke, giving its style.
+type kind int
+
+const (
+	kindSource kind = iota
+	kindBox
+	kindRapid
+	kindCut
+	kindPlunge
+)
+
+// stroke is a polyline, or a marker when it has a single point.
+type stroke struct {
+	kind   kind
+	points []geometry.Coordinates
+}
+
+// Scene is a top view of a job: its toolpath over the drawing it comes from.
+type Scene struct {
+	strokes []stroke
+}
+
+// AddSource adds the drawing read by a process, in the coordinates of the machine: the drawing is
+// shifted by the origin, as the process does. The box of the drawing is added too.
+func (s *Scene) AddSource(in io.Reader, config configuration.Config) error {
+	input, err := drawing.FromReader(in)
+	if err != nil {
+		return err
+	}
+
+	var (
+		shapeBox *geometry.Box
+		linkers  []geometry.Linker
+	)
+
+	for _, element := range input.Filter(config.Layers...) {
+		if element.Kind != drawing.KindVertex {
+			linkers = append(linkers, element.Linker)
+		}
+
+		currentBox := element.Linker.Box()
+
+		if shapeBox != nil {
+			currentBox = currentBox.Merge(*shapeBox)
+		}
+
+		shapeBox = &currentBox
+	}
+
+	if shapeBox == nil {
+		return nil
+	}
+
+	computed := config.Origin.Computed(shapeBox)
+	offset := geometry.Coordinates{X: computed[0], Y: computed[1]}
+
+	s.AddDrawing(linkers, offset)
+	s.AddBox(geometry.Box{Min: shift(shapeBox.Min, offset), Max: shift(shapeBox.Max, offset)})
+
+	return nil
+}
+
+// AddDrawing adds the elements of a drawing, shifted by the offset.
+func (s *Scene) AddDrawing(linkers []geometry.Linker, offset geometry.Coordinates) {
+	for _, linker := range linkers {
+		switch value := linker.(type) {
+		case geometry.Point:
+			s.strokes = append(s.strokes, stroke{kind: kindSource, points: []geometry.Coordinates{shift(value.Coordinates, offset)}})
+		case *geometry.Point:
+			s.strokes = append(s.strokes, stroke{kind: kindSource, points: []geometry.Coordinates{shift(value.Coordinates, offset)}})
+		case geometry.Coordinates:
+			s.strokes = append(s.strokes, stroke{kind: kindSource, points: []geometry.Coordinates{shift(value, offset)}})
+		default:
+			points := geometry.Path{linker}.Unfold().Discretize(tolerance)
+			for idx := range points {
+				points[idx] = shift(points[idx], offset)
+			}
+
+			s.strokes = append(s.strokes, stroke{kind: kindSource, points: points})
+		}
+	}
+}
+
+// AddBox adds a bounding box.
+func (s *Scene) AddBox(box geometry.Box) {
+	s.strokes = append(s.strokes, stroke{
+		kind: kindBox,
+		points: []geometry.Coordinates{
+			box.Min,
+			{X: box.Max.X, Y: box.Min.Y},
+			box.Max,
+			{X: box.Min.X, Y: box.Max.Y},
+			box.Min,
+		},
+	})
+}
+
+// AddToolpath adds the moves of a toolpath: the rapid moves, the cutting moves, and the plunges
+// (moves going down at the feed, with no move in the plane).
+func (s *Scene) AddToolpath(toolpath simulator.Toolpath) {
+	for _, move := range toolpath.Moves {
+		planar := !move.From.Equal(move.To)
+
+		switch move.Motion {
+		case simulator.MotionRapid:
+			if planar {
+				s.strokes = append(s.strokes, stroke{kind: kindRapid, points: []geometry.Coordinates{move.From, move.To}})
+			}
+		case simulator.MotionLinear:
+			switch {
+			case planar:
+				s.strokes = append(s.strokes, stroke{kind: kindCut, points: []geometry.Coordinates{move.From, move.To}})
+			case move.ToZ < move.FromZ:
+				s.strokes = append(s.strokes, stroke{kind: kindPlunge, points: []geometry.Coordinates{move.To}})
+			}
+		case simulator.MotionClockwise, simulator.MotionCounterClockwise:
+			curve := &geometry.Curve{
+				StartPoint: move.From,
+				EndPoint:   move.To,
+				Center:     move.Center,
+				Radius:     move.From.DistanceTo(move.Center),
+				Clockwise:  move.Motion == simulator.MotionCounterClockwise,
+			}
+
+			s.strokes = append(s.strokes, stroke{kind: kindCut, points: geometry.Path{curve}.Discretize(tolerance)})
+		}
+	}
+}
+
+// bounds is the box of all the strokes, with a margin.
+func (s Scene) bounds() geometry.Box {
+	output := geometry.Box{
+		Min: geometry.Coordinates{X: math.Inf(1), Y: math.Inf(1)},
+		Max: geometry.Coordinates{X: math.Inf(-1), Y: math.Inf(-1)},
+	}
+
+	for _, current := range s.strokes {
+		for _, point := range current.points {
+			output = output.Merge(point.Box())
+		}
+	}
+
+	if math.IsInf(output.Min.X, 1) {
+		return geometry.Box{Max: geometry.Coordinates{X: 1, Y: 1}}
+	}
+
+	margin := math.Max(math.Max(output.Width(), output.Height())*0.05, 1)
+
+	return geometry.Box{
+		Min: geometry.Coordinates{X: output.Min.X - margin, Y: output.Min.Y - margin},
+		Max: geometry.Coordinates{X: output.Max.X + margin, Y: output.Max.Y + margin},
+	}
+}
+
+func shift(point geometry.Coordinates, offset geometry.Coordinates) geometry.Coordinates {
+	return geometry.Coordinates{X: point.X - offset.X, Y: point.Y - offset.Y}
+}
@@ -0,0 +1,60 @@
+package preview_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/preview"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scene(t *testing.T) preview.Scene {
+	t.Helper()
+
+	toolpath, err := simulator.Simulate(strings.NewReader("G0 Z5\nG0 X10 Y10\nG1 Z-1 F100\nG1 X40\nG2 X40 Y30 I0 J10\nG0 Z5\n"), 0, gcode.PostNone)
+	require.NoError(t, err)
+
+	output := preview.Scene{}
+	output.AddDrawing([]geometry.Linker{
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: 20, Y: 20}, EndPoint: geometry.Coordinates{X: 50, Y: 20}},
+		geometry.Point{Coordinates: geometry.Coordinates{X: 25, Y: 25}},
+	}, geometry.Coordinates{X: 10, Y: 10})
+	output.AddBox(geometry.Box{Max: geometry.Coordinates{X: 60, Y: 40}})
+	output.AddToolpath(*toolpath)
+
+	return output
+}
+
+func TestSceneWriteSVG(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, scene(t).WriteSVG(&out))
+
+	svg := out.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Contains(t, svg, `<polyline class="source" points="10.000,10.000 40.000,10.000"/>`)
+	assert.Contains(t, svg, `<circle class="source" cx="15.000" cy="15.000"`)
+	assert.Contains(t, svg, `<polyline class="box" points="0.000,0.000 60.000,0.000 60.000,40.000 0.000,40.000 0.000,0.000"/>`)
+	assert.Contains(t, svg, `<polyline class="rapid" points="0.000,0.000 10.000,10.000"/>`)
+	assert.Contains(t, svg, `<circle class="plunge" cx="10.000" cy="10.000"`)
+	assert.Contains(t, svg, `<polyline class="cut" points="10.000,10.000 40.000,10.000"/>`)
+	assert.Equal(t, 2, strings.Count(svg, `class="cut"`))
+}
+
+func TestSceneWritePNG(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, scene(t).WritePNG(&out, 300))
+
+	img, err := png.Decode(&out)
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, img.Bounds().Dx())
+	assert.Less(t, img.Bounds().Dy(), 300)
+}
@@ -0,0 +1,94 @@
+package preview
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// classes are the names of the kinds of strokes in the SVG.
+var classes = map[kind]string{
+	kindSource: "source",
+	kindBox:    "box",
+	kindRapid:  "rapid",
+	kindCut:    "cut",
+	kindPlunge: "plunge",
+}
+
+// WriteSVG writes the scene as a SVG, in millimeters.
+func (s Scene) WriteSVG(out io.Writer) error {
+	bounds := s.bounds()
+	width := bounds.Width()
+	height := bounds.Height()
+	line := math.Max(width, height) / 800
+
+	if _, err := fmt.Fprintf(
+		out,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%.03fmm" height="%.03fmm" viewBox="%.03f %.03f %.03f %.03f">
+<style>
+  polyline { fill: none; stroke-width: %.04f; stroke-linecap: round; stroke-linejoin: round; }
+  circle { stroke: none; }
+  .source { stroke: #4caf50; }
+  .box { stroke: #000000; stroke-dasharray: %.04f; }
+  .rapid { stroke: #ff9800; stroke-dasharray: %.04f; }
+  .cut { stroke: #1565c0; stroke-width: %.04f; }
+  circle.source { fill: #4caf50; }
+  circle.plunge { fill: #d32f2f; }
+</style>
+<rect x="%.03f" y="%.03f" width="%.03f" height="%.03f" fill="#ffffff"/>
+<g transform="scale(1,-1)">
+`,
+		width,
+		height,
+		bounds.Min.X,
+		-bounds.Max.Y,
+		width,
+		height,
+		line,
+		line*8,
+		line*4,
+		line*2,
+		bounds.Min.X,
+		-bounds.Max.Y,
+		width,
+		height,
+	); err != nil {
+		return err
+	}
+
+	for _, current := range s.strokes {
+		if len(current.points) == 1 {
+			if _, err := fmt.Fprintf(
+				out,
+				"<circle class=\"%s\" cx=\"%.03f\" cy=\"%.03f\" r=\"%.04f\"/>\n",
+				classes[current.kind],
+				current.points[0].X,
+				current.points[0].Y,
+				line*3,
+			); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		points := make([]string, len(current.points))
+		for idx, point := range current.points {
+			points[idx] = fmt.Sprintf("%.03f,%.03f", point.X, point.Y)
+		}
+
+		if _, err := fmt.Fprintf(
+			out,
+			"<polyline class=\"%s\" points=\"%s\"/>\n",
+			classes[current.kind],
+			strings.Join(points, " "),
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprint(out, "</g>\n</svg>\n")
+
+	return err
+}
@@ -0,0 +1,146 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+const epsilon = 1e-6
+
+// Limits are the bounds a toolpath is checked against. The envelope is left out when not set.
+type Limits struct {
+	StockTop     float64
+	Envelope     *geometry.Box
+	ArcTolerance float64
+}
+
+// Problem is a fault of a program, at a line. Path names the section of the line, when known.
+type Problem struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+// String implements the Stringer interface.
+func (p Problem) String() string {
+	if p.Path != "" {
+		return fmt.Sprintf("%s, line %d: %s", p.Path, p.Line, p.Message)
+	}
+
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Check finds the problems of the toolpath: rapid moves in the material (the tool only goes down at
+// rapid speed in the hole it has drilled), moves in the material from a section to another one,
+// moves out of the envelope, cutting moves without feed, and arcs whose center is not at the same
+// distance from both ends.
+func (t Toolpath) Check(limits Limits) []Problem {
+	output := []Problem{}
+	feedChecked := false
+	section := 0
+	drilled := math.Inf(1)
+
+	for _, move := range t.Moves {
+		planar := !move.From.Equal(move.To)
+		below := move.FromZ < limits.StockTop-epsilon
+
+		if planar {
+			drilled = math.Inf(1)
+		}
+
+		if move.FromZ >= limits.StockTop-epsilon && move.ToZ < limits.StockTop-epsilon {
+			section = move.Section
+		}
+
+		switch {
+		case move.Motion == MotionRapid && (planar && below || move.ToZ < math.Min(limits.StockTop, drilled)-epsilon):
+			output = append(output, Problem{
+				Line:    move.Line,
+				Message: fmt.Sprintf("rapid move below the stock top, at Z%.3f", math.Min(move.FromZ, move.ToZ)),
+			})
+		case planar && below && move.Section != section:
+			output = append(output, Problem{
+				Line:    move.Line,
+				Message: fmt.Sprintf("move at Z%.3f from a path to another one", move.FromZ),
+			})
+
+			section = move.Section
+		}
+
+		drilled = math.Min(drilled, move.ToZ)
+
+		if move.cutting() && move.Feed <= 0 && !feedChecked {
+			output = append(output, Problem{Line: move.Line, Message: "feed rate missing before the first cutting move"})
+		}
+
+		if move.cutting() {
+			feedChecked = true
+		}
+
+		if move.Motion == MotionClockwise || move.Motion == MotionCounterClockwise {
+			startRadius := move.From.DistanceTo(move.Center)
+			endRadius := move.To.DistanceTo(move.Center)
+
+			if math.Abs(startRadius-endRadius) > limits.ArcTolerance {
+				output = append(output, Problem{
+					Line:    move.Line,
+					Message: fmt.Sprintf("arc radius is %.3f at the start and %.3f at the end", startRadius, endRadius),
+				})
+			}
+		}
+
+		if limits.Envelope != nil && !inside(move.box(), *limits.Envelope) {
+			output = append(output, Problem{
+				Line:    move.Line,
+				Message: fmt.Sprintf("move out of the envelope %s", limits.Envelope),
+			})
+		}
+	}
+
+	return output
+}
+
+// cutting tells whether the move is done at the feed.
+func (m Move) cutting() bool {
+	return m.Motion == MotionLinear || m.Motion == MotionClockwise || m.Motion == MotionCounterClockwise
+}
+
+// box is the XY box covered by the move.
+func (m Move) box() geometry.Box {
+	output := m.From.Box().Merge(m.To.Box())
+
+	if m.Motion != MotionClockwise && m.Motion != MotionCounterClockwise {
+		return output
+	}
+
+	radius := m.From.DistanceTo(m.Center)
+	start := math.Atan2(m.From.Y-m.Center.Y, m.From.X-m.Center.X)
+	sweep := geometry.Curve{
+		StartPoint: m.From,
+		EndPoint:   m.To,
+		Center:     m.Center,
+		Radius:     radius,
+		Clockwise:  m.Motion == MotionCounterClockwise,
+	}.Sweep()
+
+	for quarter := -8; quarter <= 8; quarter++ {
+		angle := float64(quarter) * math.Pi / 2
+		if (angle-start)/sweep <= 0 || (angle-start)/sweep >= 1 {
+			continue
+		}
+
+		output = output.Merge(geometry.Coordinates{
+			X: m.Center.X + radius*math.Cos(angle),
+			Y: m.Center.Y + radius*math.Sin(angle),
+		}.Box())
+	}
+
+	return output
+}
+
+func inside(box geometry.Box, envelope geometry.Box) bool {
+	return box.Min.X >= envelope.Min.X-epsilon && box.Min.Y >= envelope.Min.Y-epsilon &&
+		box.Max.X <= envelope.Max.X+epsilon && box.Max.Y <= envelope.Max.Y+epsilon
+}
@@ -0,0 +1,65 @@
+package simulator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolpathCheck(t *testing.T) {
+	check := func(t *testing.T, program string, limits simulator.Limits) []int {
+		t.Helper()
+
+		toolpath, err := simulator.Simulate(strings.NewReader(program), 0, gcode.PostNone)
+		require.NoError(t, err)
+
+		lines := []int{}
+		for _, problem := range toolpath.Check(limits) {
+			lines = append(lines, problem.Line)
+		}
+
+		return lines
+	}
+
+	limits := simulator.Limits{ArcTolerance: 0.01}
+
+	t.Run("clean", func(t *testing.T) {
+		assert.Empty(t, check(t, `G0 Z5
+G0 X10 Y10
+G1 Z-1 F100
+G2 X20 Y10 I5 J0
+G0 Z5
+G0 Z-0.5
+G1 Z-2
+G0 Z5
+`, limits))
+	})
+
+	t.Run("rapids", func(t *testing.T) {
+		assert.Equal(t, []int{3, 5}, check(t, "G0 Z5\nG1 Z-1 F100\nG0 X10\nG0 Z5\nG0 Z-2\n", limits))
+	})
+
+	t.Run("paths", func(t *testing.T) {
+		assert.Equal(t, []int{5}, check(t, ";=== Path #0 ===\nG1 Z-1 F100\nG1 X10\n;=== Path #1 ===\nG1 X20\n", limits))
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		assert.Equal(t, []int{2}, check(t, "G0 Z5\nG1 Z1\nG1 Z0 F100\n", limits))
+	})
+
+	t.Run("arc", func(t *testing.T) {
+		assert.Equal(t, []int{2}, check(t, "G0 Z1\nG2 X10 Y0 I4 J0 F100\n", limits))
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		assert.Equal(t, []int{3}, check(t, "G0 Z1\nG0 X10 Y10\nG3 X10 Y10 I-6 J0 F100\n", simulator.Limits{
+			ArcTolerance: 0.01,
+			Envelope:     &geometry.Box{Max: geometry.Coordinates{X: 100, Y: 100}},
+		}))
+	})
+}
@@ -0,0 +1,170 @@
+package simulator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+)
+
+// ErrMachine is returned when a program does not fit the machine.
+var ErrMachine = errors.New("program out of the limits of the machine")
+
+// Guard keeps a program until it is checked against the selected machine, and writes it to out when
+// it fits. The program is written as is when no machine is selected.
+type Guard struct {
+	out     io.Writer
+	config  configuration.Config
+	program bytes.Buffer
+}
+
+// NewGuard is a builder.
+func NewGuard(out io.Writer, config configuration.Config) *Guard {
+	return &Guard{out: out, config: config}
+}
+
+// Write implements the io.Writer interface.
+func (g *Guard) Write(data []byte) (int, error) {
+	if g.config.Machine == "" {
+		return g.out.Write(data)
+	}
+
+	return g.program.Write(data)
+}
+
+// Close checks the program against the selected machine and writes it.
+func (g *Guard) Close() error {
+	if g.config.Machine == "" {
+		return nil
+	}
+
+	toolpath, err := Simulate(bytes.NewReader(g.program.Bytes()), g.config.RapidFeed, g.config.Post)
+	if err != nil {
+		return fmt.Errorf("cannot check the program against the machine %s: %w", g.config.Machine, err)
+	}
+
+	problems := toolpath.Fit(g.config.Profile)
+	if len(problems) > 0 {
+		lines := make([]string, len(problems))
+		for idx, problem := range problems {
+			lines[idx] = problem.String()
+		}
+
+		return fmt.Errorf("%w %s:\n\t%s", ErrMachine, g.config.Machine, strings.Join(lines, "\n\t"))
+	}
+
+	_, err = g.out.Write(g.program.Bytes())
+
+	return err
+}
+
+// Fit finds where the toolpath does not fit the machine: moves out of the travels, cutting moves
+// faster than the max feeds, rapid moves faster than the max rates, spindle speeds out of the range
+// of the machine, and G-codes the controller does not support. Each fault is reported once per
+// section, and each G-code once.
+func (t Toolpath) Fit(profile configuration.Machine) []Problem {
+	output := []Problem{}
+	found := map[string]bool{}
+
+	report := func(key string, line int, section int, message string) {
+		if found[key] {
+			return
+		}
+
+		found[key] = true
+
+		output = append(output, Problem{Path: t.section(section), Line: line, Message: message})
+	}
+
+	for _, code := range t.Codes {
+		if !profile.Supports(code.Name) {
+			report(code.Name, code.Line, code.Section, fmt.Sprintf("%s not supported by the machine", code.Name))
+		}
+	}
+
+	for _, speed := range t.Speeds {
+		if err := profile.CheckSpindle(speed.Value); err != nil {
+			report(fmt.Sprintf("%d spindle", speed.Section), speed.Line, speed.Section, err.Error())
+		}
+	}
+
+	for idx, move := range t.Moves {
+		box := move.box()
+		if idx == 0 {
+			box = move.To.Box()
+		}
+
+		low := [3]float64{box.Min.X, box.Min.Y, math.Min(move.FromZ, move.ToZ)}
+		high := [3]float64{box.Max.X, box.Max.Y, math.Max(move.FromZ, move.ToZ)}
+
+		if idx == 0 {
+			low[2] = move.ToZ
+			high[2] = move.ToZ
+		}
+
+		for axis, limits := range profile.Axes() {
+			name := string("XYZ"[axis])
+
+			if limits.Max <= limits.Min || (low[axis] >= limits.Min-epsilon && high[axis] <= limits.Max+epsilon) {
+				continue
+			}
+
+			report(fmt.Sprintf("%d %s", move.Section, name), move.Line, move.Section, fmt.Sprintf(
+				"%s from %.3f to %.3f out of the travel [%.3f, %.3f]",
+				name,
+				low[axis],
+				high[axis],
+				limits.Min,
+				limits.Max,
+			))
+		}
+
+		if move.Motion == MotionRapid && move.Feed > 0 {
+			for _, current := range split(move) {
+				limit, _ := profile.Limits(current.direction)
+				if move.Feed <= limit+epsilon {
+					continue
+				}
+
+				report(fmt.Sprintf("%d rapid", move.Section), move.Line, move.Section, fmt.Sprintf("rapid feed %.0f above the max rate %.0f of the machine", move.Feed, limit))
+
+				break
+			}
+		}
+
+		if !move.cutting() {
+			continue
+		}
+
+		for _, current := range split(move) {
+			limit := profile.Feed(current.direction)
+			if move.Feed <= limit+epsilon {
+				continue
+			}
+
+			report(fmt.Sprintf("%d feed", move.Section), move.Line, move.Section, fmt.Sprintf("feed %.0f above the max feed %.0f of the machine", move.Feed, limit))
+
+			break
+		}
+	}
+
+	slices.SortStableFunc(output, func(a Problem, b Problem) int {
+		return a.Line - b.Line
+	})
+
+	return output
+}
+
+// section is the name of a section, the moves before the first one being in the "Start" section.
+func (t Toolpath) section(index int) string {
+	if index == 0 || index > len(t.Sections) {
+		return "Start"
+	}
+
+	return t.Sections[index-1]
+}
@@ -0,0 +1,124 @@
+package simulator_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fit(t *testing.T, program string, profile configuration.Machine, rapidFeed float64) []string {
+	t.Helper()
+
+	toolpath, err := simulator.Simulate(strings.NewReader(program), rapidFeed, gcode.PostNone)
+	require.NoError(t, err)
+
+	output := []string{}
+	for _, problem := range toolpath.Fit(profile) {
+		output = append(output, problem.String())
+	}
+
+	return output
+}
+
+func TestFit(t *testing.T) {
+	profile := configuration.Machine{
+		X:     configuration.Axis{Min: 0, Max: 100, MaxFeed: 1000},
+		Y:     configuration.Axis{Min: 0, Max: 100, MaxRate: 2000},
+		Z:     configuration.Axis{Min: -20, Max: 10, MaxFeed: 300},
+		Codes: []string{"G0", "G1", "G2", "G3", "G90"},
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		assert.Empty(t, fit(t, "G90\nG81 X-10 Y-10 Z-50 R2 F5000\n", configuration.Machine{}, 0))
+	})
+
+	t.Run("fits", func(t *testing.T) {
+		assert.Empty(t, fit(t, "G90\nG0 Z5\n;=== Path #0 ===\nG0 X10 Y10\nG1 Z-1 F200\nG1 X90 F900\nG2 X90 Y30 I0 J10\n", profile, 0))
+	})
+
+	t.Run("travel", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"Path #1, line 6: X from 90.000 to 110.000 out of the travel [0.000, 100.000]",
+			"Path #1, line 7: Z from -30.000 to 5.000 out of the travel [-20.000, 10.000]",
+			"Path #1, line 7: feed 500 above the max feed 300 of the machine",
+		}, fit(t, "G0 Z5\n;=== Path #0 ===\nG0 X10 Y10\nG1 X90 F500\n;=== Path #1 ===\nG3 X90 Y50 I0 J20\nG1 Z-30\nG0 X200\n", profile, 0))
+	})
+
+	t.Run("feeds", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"Start, line 1: feed 500 above the max feed 300 of the machine",
+			"Path #0, line 4: feed 2500 above the max feed 2000 of the machine",
+		}, fit(t, "G1 Z-1 F500\n;=== Path #0 ===\nG1 X10 F900\nG1 Y10 F2500\nG1 X20 Y20\n", profile, 0))
+	})
+
+	t.Run("spindle", func(t *testing.T) {
+		spindle := configuration.Machine{SpindleMin: 8000, SpindleMax: 24000}
+
+		assert.Equal(t, []string{
+			"Path #0, line 3: spindle speed 30000 out of the range [8000, 24000] of the machine",
+		}, fit(t, "M3 S12000\n;=== Path #0 ===\nM3 S30000\nM5 S0\nM3 S40000\n", spindle, 0))
+	})
+
+	t.Run("rapids", func(t *testing.T) {
+		assert.Empty(t, fit(t, "G0 X10 Y10\n", profile, 2000))
+		assert.Equal(t, []string{
+			"Start, line 1: rapid feed 3000 above the max rate 2000 of the machine",
+		}, fit(t, "G0 Y10\nG0 X10\n;=== Path #0 ===\nG0 X20\n", profile, 3000))
+	})
+
+	t.Run("codes", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"Start, line 1: G21 not supported by the machine",
+			"Path #0, line 3: G4 not supported by the machine",
+		}, fit(t, "G21\n;=== Path #0 ===\nG4 P1\n;=== Path #1 ===\nG4 P1\nG21\n", profile, 0))
+	})
+}
+
+func TestGuard(t *testing.T) {
+	config := configuration.Config{
+		Machine: "small",
+		Profile: configuration.Machine{X: configuration.Axis{Min: 0, Max: 100}},
+	}
+
+	t.Run("fits", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		guard := simulator.NewGuard(out, config)
+
+		_, err := fmt.Fprint(guard, "G0 X10\n")
+		require.NoError(t, err)
+		assert.Empty(t, out.String())
+
+		require.NoError(t, guard.Close())
+		assert.Equal(t, "G0 X10\n", out.String())
+	})
+
+	t.Run("out of the travel", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		guard := simulator.NewGuard(out, config)
+
+		_, err := fmt.Fprint(guard, ";=== Path #0 ===\nG0 X110\n")
+		require.NoError(t, err)
+
+		err = guard.Close()
+		require.ErrorIs(t, err, simulator.ErrMachine)
+		assert.Contains(t, err.Error(), "Path #0, line 2: X from 110.000 to 110.000 out of the travel")
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("no machine", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		guard := simulator.NewGuard(out, configuration.Config{})
+
+		_, err := fmt.Fprint(guard, "G0 X110\n")
+		require.NoError(t, err)
+		assert.Equal(t, "G0 X110\n", out.String())
+		require.NoError(t, guard.Close())
+	})
+}
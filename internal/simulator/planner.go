@@ -0,0 +1,284 @@
+package simulator
+
+import (
+	"math"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+)
+
+// arcTolerance is the chord tolerance used to split the arcs in straight blocks, as GRBL does.
+const arcTolerance = 0.002
+
+// Estimate is the time a program takes on a machine. RapidUnknown tells that rapid moves are left
+// out of the rapid time, no rate being known for them.
+type Estimate struct {
+	Cutting      time.Duration
+	Rapid        time.Duration
+	Dwell        time.Duration
+	RapidUnknown bool
+	Sections     []SectionEstimate
+}
+
+// SectionEstimate is the time taken by a section of a program.
+type SectionEstimate struct {
+	Name         string
+	Cutting      time.Duration
+	Rapid        time.Duration
+	Dwell        time.Duration
+	RapidUnknown bool
+}
+
+// block is a straight move planned with the acceleration of the machine. Speeds are in
+// millimeters per second.
+type block struct {
+	move         Move
+	length       float64
+	direction    [3]float64
+	speed        float64
+	acceleration float64
+	junction     float64
+	entry        float64
+}
+
+// Total is the time taken by the whole program.
+func (e Estimate) Total() time.Duration {
+	return e.Cutting + e.Rapid + e.Dwell
+}
+
+// Estimate plans the moves of the toolpath like the GRBL planner does: the tool speeds up and slows
+// down with the acceleration of the axes, and goes through the corners at the speed keeping it
+// within the junction deviation. It stops on dwells and at the end. The rapid moves go at the max
+// rates of the axes, within the rapid feed when set; their time is unknown when none of them is set.
+func (t Toolpath) Estimate(machine configuration.Machine, rapidFeed float64) Estimate {
+	output := Estimate{}
+	sections := make([]SectionEstimate, len(t.Sections)+1)
+
+	for idx := range sections {
+		sections[idx].Name = t.section(idx)
+	}
+
+	blocks := []block{}
+
+	flush := func() {
+		for idx, current := range plan(blocks, machine.JunctionDeviation) {
+			section := &sections[blocks[idx].move.Section]
+
+			if blocks[idx].move.Motion == MotionRapid {
+				output.Rapid += current
+				section.Rapid += current
+
+				continue
+			}
+
+			output.Cutting += current
+			section.Cutting += current
+		}
+
+		blocks = blocks[:0]
+	}
+
+	for _, move := range t.Moves {
+		if move.Motion == MotionDwell {
+			flush()
+
+			output.Dwell += move.Duration
+			sections[move.Section].Dwell += move.Duration
+
+			continue
+		}
+
+		feed := move.Feed
+		if move.Motion == MotionRapid {
+			feed = math.Inf(1)
+			if rapidFeed > 0 {
+				feed = rapidFeed
+			}
+		}
+
+		for _, current := range split(move) {
+			rate, acceleration := machine.Limits(current.direction)
+
+			current.speed = math.Min(feed, rate) / 60
+			current.acceleration = acceleration
+
+			if math.IsInf(current.speed, 1) || current.speed <= 0 {
+				if move.Motion == MotionRapid {
+					output.RapidUnknown = true
+					sections[move.Section].RapidUnknown = true
+				}
+
+				flush()
+
+				continue
+			}
+
+			blocks = append(blocks, current)
+		}
+	}
+
+	flush()
+
+	for _, section := range sections {
+		if section.Total() > 0 || section.RapidUnknown {
+			output.Sections = append(output.Sections, section)
+		}
+	}
+
+	return output
+}
+
+// split cuts the move in straight blocks, the arcs being followed by chords.
+func split(move Move) []block {
+	points := [][3]float64{{move.From.X, move.From.Y, move.FromZ}}
+
+	switch move.Motion {
+	case MotionClockwise, MotionCounterClockwise:
+		radius := move.From.DistanceTo(move.Center)
+		sweep := geometry.Curve{
+			StartPoint: move.From,
+			EndPoint:   move.To,
+			Center:     move.Center,
+			Radius:     radius,
+			Clockwise:  move.Motion == MotionCounterClockwise,
+		}.Sweep()
+
+		count := 1
+		if radius > arcTolerance {
+			chord := 2 * math.Sqrt(arcTolerance*(2*radius-arcTolerance))
+			count = max(1, int(math.Ceil(math.Abs(sweep)*radius/chord)))
+		}
+
+		start := math.Atan2(move.From.Y-move.Center.Y, move.From.X-move.Center.X)
+
+		for idx := 1; idx < count; idx++ {
+			fraction := float64(idx) / float64(count)
+			angle := start + sweep*fraction
+
+			points = append(points, [3]float64{
+				move.Center.X + radius*math.Cos(angle),
+				move.Center.Y + radius*math.Sin(angle),
+				move.FromZ + (move.ToZ-move.FromZ)*fraction,
+			})
+		}
+	}
+
+	points = append(points, [3]float64{move.To.X, move.To.Y, move.ToZ})
+
+	output := []block{}
+
+	for idx := 1; idx < len(points); idx++ {
+		delta := [3]float64{}
+		length := 0.0
+
+		for axis := range delta {
+			delta[axis] = points[idx][axis] - points[idx-1][axis]
+			length += delta[axis] * delta[axis]
+		}
+
+		length = math.Sqrt(length)
+		if length < epsilon {
+			continue
+		}
+
+		for axis := range delta {
+			delta[axis] /= length
+		}
+
+		output = append(output, block{move: move, length: length, direction: delta})
+	}
+
+	return output
+}
+
+// plan gives the time taken by each block, the tool starting and ending at rest.
+func plan(blocks []block, deviation float64) []time.Duration {
+	for idx := range blocks {
+		if idx == 0 {
+			continue
+		}
+
+		blocks[idx].junction = junction(blocks[idx-1], blocks[idx], deviation)
+	}
+
+	exit := 0.0
+
+	for idx := len(blocks) - 1; idx >= 0; idx-- {
+		blocks[idx].entry = math.Min(blocks[idx].junction, reachable(exit, blocks[idx].acceleration, blocks[idx].length))
+		exit = blocks[idx].entry
+	}
+
+	for idx := 1; idx < len(blocks); idx++ {
+		previous := blocks[idx-1]
+		blocks[idx].entry = math.Min(blocks[idx].entry, reachable(previous.entry, previous.acceleration, previous.length))
+	}
+
+	output := make([]time.Duration, len(blocks))
+
+	for idx, current := range blocks {
+		exit := 0.0
+		if idx+1 < len(blocks) {
+			exit = blocks[idx+1].entry
+		}
+
+		output[idx] = time.Duration(trapezoid(current, exit) * float64(time.Second))
+	}
+
+	return output
+}
+
+// junction is the max speed from a block to the next one: the speed keeping the tool within the
+// deviation on a circle tangent to both blocks.
+func junction(previous block, next block, deviation float64) float64 {
+	cosine := 0.0
+	for axis := range previous.direction {
+		cosine -= previous.direction[axis] * next.direction[axis]
+	}
+
+	speed := math.Min(previous.speed, next.speed)
+
+	switch {
+	case cosine > 1-1e-6:
+		return 0
+	case cosine < -1+1e-6:
+		return speed
+	}
+
+	acceleration := math.Min(previous.acceleration, next.acceleration)
+	if math.IsInf(acceleration, 1) {
+		return speed
+	}
+
+	sinus := math.Sqrt(0.5 * (1 - cosine))
+
+	return math.Min(speed, math.Sqrt(acceleration*deviation*sinus/(1-sinus)))
+}
+
+// reachable is the speed reached from a speed over a length with an acceleration.
+func reachable(speed float64, acceleration float64, length float64) float64 {
+	return math.Sqrt(speed*speed + 2*acceleration*length)
+}
+
+// trapezoid is the time (in seconds) taken by a block, speeding up from its entry speed, cruising
+// at its speed, and slowing down to the exit speed.
+func trapezoid(current block, exit float64) float64 {
+	entry := current.entry
+	speed := current.speed
+	acceleration := current.acceleration
+
+	if math.IsInf(acceleration, 1) {
+		return current.length / speed
+	}
+
+	accelerating := (speed*speed - entry*entry) / (2 * acceleration)
+	decelerating := (speed*speed - exit*exit) / (2 * acceleration)
+
+	if accelerating+decelerating <= current.length {
+		return (speed-entry)/acceleration + (speed-exit)/acceleration + (current.length-accelerating-decelerating)/speed
+	}
+
+	peak := math.Sqrt((2*acceleration*current.length + entry*entry + exit*exit) / 2)
+
+	return (peak-entry)/acceleration + (peak-exit)/acceleration
+}
@@ -0,0 +1,112 @@
+package simulator_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func estimate(t *testing.T, program string, machine configuration.Machine, rapidFeed float64) simulator.Estimate {
+	t.Helper()
+
+	toolpath, err := simulator.Simulate(strings.NewReader(program), rapidFeed, gcode.PostNone)
+	require.NoError(t, err)
+
+	return toolpath.Estimate(machine, rapidFeed)
+}
+
+func TestEstimate(t *testing.T) {
+	limited := func(acceleration float64, deviation float64) configuration.Machine {
+		axis := configuration.Axis{Acceleration: acceleration}
+
+		return configuration.Machine{X: axis, Y: axis, Z: axis, JunctionDeviation: deviation}
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		output := estimate(t, "G1 X10 F60\nG0 X20\nG4 P2\n", configuration.Machine{}, 0)
+
+		assert.Equal(t, 10*time.Second, output.Cutting)
+		assert.Zero(t, output.Rapid)
+		assert.True(t, output.RapidUnknown)
+		assert.Equal(t, 2*time.Second, output.Dwell)
+		assert.Equal(t, 12*time.Second, output.Total())
+	})
+
+	t.Run("trapezoid", func(t *testing.T) {
+		output := estimate(t, "G1 X20 F600\n", limited(10, 0.01), 0)
+
+		assert.InDelta(t, 3, output.Cutting.Seconds(), 1e-6)
+	})
+
+	t.Run("triangle", func(t *testing.T) {
+		output := estimate(t, "G1 X2 F600\n", limited(10, 0.01), 0)
+
+		assert.InDelta(t, 0.894427, output.Cutting.Seconds(), 1e-6)
+	})
+
+	t.Run("straight junction", func(t *testing.T) {
+		output := estimate(t, "G1 X20 F600\nG1 X40\n", limited(10, 0.01), 0)
+
+		assert.InDelta(t, 5, output.Cutting.Seconds(), 1e-6)
+	})
+
+	t.Run("corner", func(t *testing.T) {
+		stop := estimate(t, "G1 X20 F600\nG1 Y20\n", limited(10, 0), 0)
+		smooth := estimate(t, "G1 X20 F600\nG1 Y20\n", limited(10, 0.01), 0)
+
+		assert.InDelta(t, 6, stop.Cutting.Seconds(), 1e-6)
+		assert.Less(t, smooth.Cutting.Seconds(), 6.0)
+		assert.Greater(t, smooth.Cutting.Seconds(), 5.5)
+	})
+
+	t.Run("arc", func(t *testing.T) {
+		output := estimate(t, "G1 X10 F600\nG3 X-10 Y0 I-10 J0\n", limited(10, 0.01), 0)
+
+		assert.Greater(t, output.Cutting.Seconds(), (10+10*3.14159265)/10)
+		assert.Less(t, output.Cutting.Seconds(), (10+10*3.14159265)/10+2)
+	})
+
+	t.Run("rapid", func(t *testing.T) {
+		machine := configuration.Machine{X: configuration.Axis{MaxRate: 6000}}
+
+		assert.Equal(t, time.Second, estimate(t, "G0 X100\n", machine, 0).Rapid)
+		assert.Equal(t, 2*time.Second, estimate(t, "G0 X100\n", machine, 3000).Rapid)
+		assert.False(t, estimate(t, "G0 X100\n", machine, 0).RapidUnknown)
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		output := estimate(t, "G0 X10\n;=== Path #0 ===\nG1 X20 F60\n;=== Path #1 ===\nG1 X40\n", configuration.Machine{}, 600)
+
+		require.Len(t, output.Sections, 3)
+
+		assert.Equal(t, "Start", output.Sections[0].Name)
+		assert.Equal(t, time.Second, output.Sections[0].Rapid)
+		assert.Equal(t, simulator.SectionEstimate{Name: "Path #0", Cutting: 10 * time.Second}, output.Sections[1])
+		assert.Equal(t, simulator.SectionEstimate{Name: "Path #1", Cutting: 20 * time.Second}, output.Sections[2])
+	})
+}
+
+func TestEstimateWrite(t *testing.T) {
+	output := estimate(t, "G1 X10 F60\n;=== Path #0 ===\nG0 X20\nG1 X30\n", configuration.Machine{}, 0)
+
+	text := &bytes.Buffer{}
+	require.NoError(t, output.Write(text, configuration.ReportText))
+	assert.Equal(
+		t,
+		"; Estimated time: at least 20s (cutting 20s, rapid unknown, dwell 0s)\n"+
+			";\tStart: 10s (cutting 10s, rapid 0s)\n"+
+			";\tPath #0: at least 10s (cutting 10s, rapid unknown)\n",
+		text.String(),
+	)
+
+	data := &bytes.Buffer{}
+	require.NoError(t, output.Write(data, configuration.ReportJSON))
+	assert.Contains(t, data.String(), "\"rapid\": null")
+}
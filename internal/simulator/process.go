@@ -0,0 +1,49 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+)
+
+// ErrProblems is returned when problems are found in a program.
+var ErrProblems = errors.New("problems found")
+
+// Process is the simulation process. It writes the figures of the program and its problems.
+func Process(in io.Reader, out io.Writer, config configuration.Config, limits Limits) error {
+	toolpath, err := Simulate(in, config.RapidFeed, config.Post)
+	if err != nil {
+		return err
+	}
+
+	estimate := toolpath.Estimate(config.Profile, config.RapidFeed)
+
+	if _, err := fmt.Fprintf(
+		out,
+		"\tMoves: %d\n\tDistance: %.01f mm\n\tDuration: %s (cutting %s, rapid %s, dwell %s)\n",
+		len(toolpath.Moves),
+		toolpath.Distance,
+		total(estimate.Total(), estimate.RapidUnknown),
+		estimate.Cutting.Round(precision),
+		rapid(estimate.Rapid, estimate.RapidUnknown),
+		estimate.Dwell.Round(precision),
+	); err != nil {
+		return err
+	}
+
+	problems := append(toolpath.Check(limits), toolpath.Fit(config.Profile)...)
+
+	for _, problem := range problems {
+		if _, err := fmt.Fprintf(out, "\t* %s\n", problem); err != nil {
+			return err
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d", ErrProblems, len(problems))
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/configuration"
+)
+
+// precision is the rounding of the durations in the text report.
+const precision = 100 * time.Millisecond
+
+// jsonEstimate is the JSON form of an estimate, durations being in seconds. The rapid time is null
+// when it is unknown.
+type jsonEstimate struct {
+	Total    float64       `json:"total"`
+	Cutting  float64       `json:"cutting"`
+	Rapid    *float64      `json:"rapid"`
+	Dwell    float64       `json:"dwell"`
+	Sections []jsonSection `json:"sections"`
+}
+
+// jsonSection is the JSON form of the estimate of a section.
+type jsonSection struct {
+	Name    string   `json:"name"`
+	Total   float64  `json:"total"`
+	Cutting float64  `json:"cutting"`
+	Rapid   *float64 `json:"rapid"`
+	Dwell   float64  `json:"dwell"`
+}
+
+// Total is the time taken by the section.
+func (s SectionEstimate) Total() time.Duration {
+	return s.Cutting + s.Rapid + s.Dwell
+}
+
+// Write writes the estimate in the form of the report.
+func (e Estimate) Write(out io.Writer, report configuration.Report) error {
+	switch report {
+	case configuration.ReportNone:
+		return nil
+	case configuration.ReportJSON:
+		return e.writeJSON(out)
+	default:
+		return e.writeText(out)
+	}
+}
+
+func (e Estimate) writeText(out io.Writer) error {
+	if _, err := fmt.Fprintf(
+		out,
+		"; Estimated time: %s (cutting %s, rapid %s, dwell %s)\n",
+		total(e.Total(), e.RapidUnknown),
+		e.Cutting.Round(precision),
+		rapid(e.Rapid, e.RapidUnknown),
+		e.Dwell.Round(precision),
+	); err != nil {
+		return err
+	}
+
+	for _, section := range e.Sections {
+		if _, err := fmt.Fprintf(
+			out,
+			";\t%s: %s (cutting %s, rapid %s)\n",
+			section.Name,
+			total(section.Total(), section.RapidUnknown),
+			section.Cutting.Round(precision),
+			rapid(section.Rapid, section.RapidUnknown),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e Estimate) writeJSON(out io.Writer) error {
+	output := jsonEstimate{
+		Total:    e.Total().Seconds(),
+		Cutting:  e.Cutting.Seconds(),
+		Rapid:    seconds(e.Rapid, e.RapidUnknown),
+		Dwell:    e.Dwell.Seconds(),
+		Sections: []jsonSection{},
+	}
+
+	for _, section := range e.Sections {
+		output.Sections = append(output.Sections, jsonSection{
+			Name:    section.Name,
+			Total:   section.Total().Seconds(),
+			Cutting: section.Cutting.Seconds(),
+			Rapid:   seconds(section.Rapid, section.RapidUnknown),
+			Dwell:   section.Dwell.Seconds(),
+		})
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(output)
+}
+
+// total is the text of a total time, which is a lower bound when the rapid time is unknown.
+func total(duration time.Duration, rapidUnknown bool) string {
+	if rapidUnknown {
+		return "at least " + duration.Round(precision).String()
+	}
+
+	return duration.Round(precision).String()
+}
+
+// rapid is the text of a rapid time.
+func rapid(duration time.Duration, unknown bool) string {
+	if unknown {
+		return "unknown"
+	}
+
+	return duration.Round(precision).String()
+}
+
+// seconds is the JSON form of a rapid time.
+func seconds(duration time.Duration, unknown bool) *float64 {
+	if unknown {
+		return nil
+	}
+
+	output := duration.Seconds()
+
+	return &output
+}
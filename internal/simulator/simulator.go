@@ -0,0 +1,431 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/machine"
+)
+
+const (
+	// MotionRapid is a move at rapid speed (G0).
+	MotionRapid = 0
+
+	// MotionLinear is a straight move at the feed (G1).
+	MotionLinear = 1
+
+	// MotionClockwise is a clockwise arc (G2).
+	MotionClockwise = 2
+
+	// MotionCounterClockwise is a counter-clockwise arc (G3).
+	MotionCounterClockwise = 3
+
+	// MotionDwell is a pause (G4).
+	MotionDwell = 4
+
+	// motionNone is the state after the cancellation of a canned cycle (G80).
+	motionNone = -1
+
+	inch = 25.4
+
+	// chipBreak is the short retract in millimeters between the pecks of G73.
+	chipBreak = 0.2
+)
+
+// ErrUnsupported is returned when a code cannot be simulated.
+var ErrUnsupported = errors.New("unsupported")
+
+// Move is a move of the tool, positions being in millimeters, feeds in millimeters per minute (the
+// rapid feed for the rapid moves, 0 when not set), and Section the count of the section comments ("=== ... ===", written before each path or hole) found
+// before the move.
+type Move struct {
+	Line     int
+	Motion   int
+	From     geometry.Coordinates
+	FromZ    float64
+	To       geometry.Coordinates
+	ToZ      float64
+	Center   geometry.Coordinates
+	Feed     float64
+	Distance float64
+	Duration time.Duration
+	Section  int
+}
+
+// Code is a G-code of a program, with the section it is found in.
+type Code struct {
+	Line    int
+	Name    string
+	Section int
+}
+
+// Speed is a spindle speed (S word) of a program, with the section it is found in.
+type Speed struct {
+	Line    int
+	Value   float64
+	Section int
+}
+
+// Toolpath is a program replayed by the simulator. Sections are the names of the sections, the
+// moves of the section numbered n (from 1) being named by Sections[n-1].
+type Toolpath struct {
+	Moves    []Move
+	Codes    []Code
+	Speeds   []Speed
+	Sections []string
+	Distance float64
+	Duration time.Duration
+}
+
+// Simulator replays programs, keeping the modal state between the blocks.
+type Simulator struct {
+	path     *machine.Path
+	moves    []Move
+	feed     float64
+	motion   int
+	relative bool
+	scale    float64
+	codes    []Code
+	speeds   []Speed
+	sections []string
+	cycle    cycle
+	post     gcode.Post
+}
+
+// cycle is the modal state of the canned cycles.
+type cycle struct {
+	active     bool
+	initialZ   float64
+	retractToR bool
+	plane      float64
+	deep       float64
+	peck       float64
+	dwell      float64
+}
+
+// New is a builder. The tool starts at the position, the rapid moves are counted at rapidFeed, and
+// the dwells are read in the unit of the post-processor.
+func New(x float64, y float64, z float64, rapidFeed float64, post gcode.Post) *Simulator {
+	path := machine.NewPath(x, y, z)
+	path.RapidFeed = rapidFeed
+
+	return &Simulator{
+		path:   path,
+		motion: motionNone,
+		scale:  1,
+		post:   post,
+	}
+}
+
+// Simulate replays a program, the tool starting at the origin.
+func Simulate(in io.Reader, rapidFeed float64, post gcode.Post) (*Toolpath, error) {
+	return New(0, 0, 0, rapidFeed, post).Run(in)
+}
+
+// Run replays a program. The toolpath holds all the moves replayed by the simulator so far.
+func (s *Simulator) Run(in io.Reader) (*Toolpath, error) {
+	blocks, err := gcode.Parse(in)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		if err := s.block(block); err != nil {
+			return nil, fmt.Errorf("line %d: %w", block.Line, err)
+		}
+	}
+
+	return &Toolpath{
+		Moves:    append([]Move{}, s.moves...),
+		Codes:    append([]Code{}, s.codes...),
+		Speeds:   append([]Speed{}, s.speeds...),
+		Sections: append([]string{}, s.sections...),
+		Distance: s.path.Distance,
+		Duration: s.path.Duration,
+	}, nil
+}
+
+func (s *Simulator) block(block gcode.Block) error {
+	dwell := false
+
+	if strings.HasPrefix(block.Comment, "===") {
+		s.sections = append(s.sections, strings.Trim(block.Comment, "= "))
+	}
+
+	for _, code := range block.Codes('G') {
+		s.codes = append(s.codes, Code{Line: block.Line, Name: "G" + number(code), Section: len(s.sections)})
+
+		switch code {
+		case 0, 1, 2, 3:
+			s.motion = int(code)
+			s.cycle.active = false
+		case 4:
+			dwell = true
+		case 17, 40, 49, 54, 55, 56, 57, 58, 59, 61, 64, 94:
+		case 20:
+			s.scale = inch
+		case 21:
+			s.scale = 1
+		case 73, 81, 82, 83:
+			if !s.cycle.active {
+				s.cycle.active = true
+				s.cycle.initialZ = s.path.CurrentZ
+			}
+
+			s.motion = int(code)
+		case 80:
+			s.motion = motionNone
+			s.cycle.active = false
+		case 90:
+			s.relative = false
+		case 91:
+			s.relative = true
+		case 98:
+			s.cycle.retractToR = false
+		case 99:
+			s.cycle.retractToR = true
+		default:
+			return fmt.Errorf("%w code G%s", ErrUnsupported, number(code))
+		}
+	}
+
+	if feed, ok := block.Value('F'); ok {
+		s.feed = feed * s.scale
+	}
+
+	if speed, ok := block.Value('S'); ok {
+		s.speeds = append(s.speeds, Speed{Line: block.Line, Value: speed, Section: len(s.sections)})
+	}
+
+	if dwell {
+		value, _ := block.Value('P')
+
+		return s.dwell(block.Line, s.post.DwellSeconds(value))
+	}
+
+	if !block.Has('X', 'Y', 'Z') {
+		return nil
+	}
+
+	x, y, z := s.target(block)
+
+	switch s.motion {
+	case MotionRapid:
+		return s.record(block.Line, MotionRapid, geometry.Coordinates{}, s.path.RapidFeed, func() error {
+			return s.path.RapidTo(x, y, z, io.Discard)
+		})
+	case MotionLinear:
+		return s.record(block.Line, MotionLinear, geometry.Coordinates{}, s.feed, func() error {
+			return s.path.MoveTo(x, y, z, s.feed, io.Discard)
+		})
+	case MotionClockwise, MotionCounterClockwise:
+		return s.arc(block, x, y, z)
+	case 73, 81, 82, 83:
+		return s.drill(block, x, y)
+	}
+
+	return errors.New("move without motion mode")
+}
+
+// target is the end position of the move of the block.
+func (s *Simulator) target(block gcode.Block) (float64, float64, float64) {
+	output := [3]float64{s.path.CurrentPosition.X, s.path.CurrentPosition.Y, s.path.CurrentZ}
+
+	for idx, letter := range []byte{'X', 'Y', 'Z'} {
+		value, ok := block.Value(letter)
+		if !ok {
+			continue
+		}
+
+		if s.relative {
+			output[idx] += value * s.scale
+
+			continue
+		}
+
+		output[idx] = value * s.scale
+	}
+
+	return output[0], output[1], output[2]
+}
+
+// arc follows an arc given with its center (I and J, from the start) or with its radius (R, negative
+// for arcs over a half circle).
+func (s *Simulator) arc(block gcode.Block, x float64, y float64, z float64) error {
+	start := s.path.CurrentPosition
+	end := geometry.Coordinates{X: x, Y: y}
+
+	offsetX, hasI := block.Value('I')
+	offsetY, hasJ := block.Value('J')
+	center := geometry.Coordinates{X: start.X + offsetX*s.scale, Y: start.Y + offsetY*s.scale}
+
+	if radius, ok := block.Value('R'); ok && !hasI && !hasJ {
+		var err error
+
+		center, err = arcCenter(start, end, radius*s.scale, s.motion == MotionClockwise)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.record(block.Line, s.motion, center, s.feed, func() error {
+		return s.path.HelixTo(x, y, z, center.X, center.Y, s.motion == MotionCounterClockwise, s.feed, io.Discard)
+	})
+}
+
+// arcCenter is the center of the arc of the radius joining two points. The center is on the right of
+// the chord for short clockwise arcs.
+func arcCenter(start geometry.Coordinates, end geometry.Coordinates, radius float64, clockwise bool) (geometry.Coordinates, error) {
+	chordX := end.X - start.X
+	chordY := end.Y - start.Y
+	chord := math.Hypot(chordX, chordY)
+
+	if chord < 1e-9 {
+		return geometry.Coordinates{}, errors.New("full circle given with a radius")
+	}
+
+	height := math.Sqrt(math.Max(0, radius*radius-chord*chord/4))
+
+	side := 1.0
+	if clockwise == (radius > 0) {
+		side = -1
+	}
+
+	return geometry.Coordinates{
+		X: (start.X+end.X)/2 - side*height*chordY/chord,
+		Y: (start.Y+end.Y)/2 + side*height*chordX/chord,
+	}, nil
+}
+
+// drill replays a canned cycle on a point: rapid to the point and to the R plane, down to the deep
+// at the feed (by pecks for G83, going back to the R plane after each one, and for G73, going back a
+// short distance to break the chip), and back up at rapid speed to the initial Z (G98) or to the R
+// plane (G99).
+func (s *Simulator) drill(block gcode.Block, x float64, y float64) error {
+	if s.relative {
+		return fmt.Errorf("%w canned cycle in relative mode", ErrUnsupported)
+	}
+
+	if value, ok := block.Value('R'); ok {
+		s.cycle.plane = value * s.scale
+	}
+
+	if value, ok := block.Value('Z'); ok {
+		s.cycle.deep = value * s.scale
+	}
+
+	if value, ok := block.Value('Q'); ok {
+		s.cycle.peck = value * s.scale
+	}
+
+	if value, ok := block.Value('P'); ok {
+		s.cycle.dwell = s.post.DwellSeconds(value)
+	}
+
+	rapid := func(x float64, y float64, z float64) error {
+		return s.record(block.Line, MotionRapid, geometry.Coordinates{}, s.path.RapidFeed, func() error {
+			return s.path.RapidTo(x, y, z, io.Discard)
+		})
+	}
+
+	if err := rapid(x, y, s.path.CurrentZ); err != nil {
+		return err
+	}
+
+	if err := rapid(x, y, s.cycle.plane); err != nil {
+		return err
+	}
+
+	depth := s.cycle.plane
+
+	for depth > s.cycle.deep {
+		next := s.cycle.deep
+		if (s.motion == 83 || s.motion == 73) && s.cycle.peck > 0 {
+			next = math.Max(depth-s.cycle.peck, s.cycle.deep)
+		}
+
+		if err := s.record(block.Line, MotionLinear, geometry.Coordinates{}, s.feed, func() error {
+			return s.path.MoveTo(x, y, next, s.feed, io.Discard)
+		}); err != nil {
+			return err
+		}
+
+		depth = next
+
+		if depth > s.cycle.deep {
+			back := s.cycle.plane
+			if s.motion == 73 {
+				back = math.Min(depth+chipBreak, s.cycle.plane)
+			}
+
+			if err := rapid(x, y, back); err != nil {
+				return err
+			}
+
+			if err := rapid(x, y, depth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.motion == 82 && s.cycle.dwell > 0 {
+		if err := s.dwell(block.Line, s.cycle.dwell); err != nil {
+			return err
+		}
+	}
+
+	retract := math.Max(s.cycle.initialZ, s.cycle.plane)
+	if s.cycle.retractToR {
+		retract = s.cycle.plane
+	}
+
+	return rapid(x, y, retract)
+}
+
+// dwell pauses for a number of seconds.
+func (s *Simulator) dwell(line int, seconds float64) error {
+	return s.record(line, MotionDwell, geometry.Coordinates{}, 0, func() error {
+		s.path.Duration += time.Duration(seconds * float64(time.Second))
+
+		return nil
+	})
+}
+
+// record runs a move and keeps what it covers.
+func (s *Simulator) record(line int, motion int, center geometry.Coordinates, feed float64, move func() error) error {
+	current := Move{
+		Line:    line,
+		Motion:  motion,
+		From:    s.path.CurrentPosition,
+		FromZ:   s.path.CurrentZ,
+		Center:  center,
+		Feed:    feed,
+		Section: len(s.sections),
+	}
+
+	distance := s.path.Distance
+	duration := s.path.Duration
+
+	if err := move(); err != nil {
+		return err
+	}
+
+	current.To = s.path.CurrentPosition
+	current.ToZ = s.path.CurrentZ
+	current.Distance = s.path.Distance - distance
+	current.Duration = s.path.Duration - duration
+
+	s.moves = append(s.moves, current)
+
+	return nil
+}
+
+func number(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
@@ -0,0 +1,131 @@
+package simulator_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/landru29/cnc-drilling/internal/gcode"
+	"github.com/landru29/cnc-drilling/internal/geometry"
+	"github.com/landru29/cnc-drilling/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Run("moves", func(t *testing.T) {
+		toolpath, err := simulator.Simulate(strings.NewReader(`G90
+G21
+G0 Z5
+G0 X10 Y0
+G1 Z-1 F60
+G1 X20 F120
+G3 X30 Y10 I0 J10
+G4 P2
+G91
+G1 X-10
+G0 Z6
+`), 0, gcode.PostNone)
+		require.NoError(t, err)
+		require.Len(t, toolpath.Moves, 8)
+
+		assert.Equal(t, simulator.MotionLinear, toolpath.Moves[2].Motion)
+		assert.InDelta(t, 6, toolpath.Moves[2].Distance, 1e-9)
+		assert.Equal(t, 6*time.Second, toolpath.Moves[2].Duration)
+
+		arc := toolpath.Moves[4]
+		assert.Equal(t, simulator.MotionCounterClockwise, arc.Motion)
+		assert.Equal(t, geometry.Coordinates{X: 20, Y: 10}, arc.Center)
+		assert.InDelta(t, 5*3.14159265, arc.Distance, 1e-6)
+
+		assert.Equal(t, simulator.MotionDwell, toolpath.Moves[5].Motion)
+		assert.Equal(t, 2*time.Second, toolpath.Moves[5].Duration)
+
+		assert.Equal(t, geometry.Coordinates{X: 20, Y: 10}, toolpath.Moves[6].To)
+		assert.InDelta(t, 5, toolpath.Moves[7].ToZ, 1e-9)
+		assert.Zero(t, toolpath.Moves[0].Duration)
+	})
+
+	t.Run("inches and radius", func(t *testing.T) {
+		toolpath, err := simulator.Simulate(strings.NewReader("G20\nG1 X1 F10\nG2 X2 Y0 R-0.5\n"), 0, gcode.PostNone)
+		require.NoError(t, err)
+		require.Len(t, toolpath.Moves, 2)
+
+		assert.InDelta(t, 25.4, toolpath.Moves[0].To.X, 1e-9)
+		assert.InDelta(t, 254, toolpath.Moves[0].Feed, 1e-9)
+		assert.InDelta(t, 38.1, toolpath.Moves[1].Center.X, 1e-9)
+		assert.InDelta(t, 0, toolpath.Moves[1].Center.Y, 1e-9)
+	})
+
+	t.Run("canned cycle", func(t *testing.T) {
+		toolpath, err := simulator.Simulate(strings.NewReader("G0 Z10\nG98\nG83 X5 Y5 Z-3 R2 Q2 F60\nG80\n"), 0, gcode.PostNone)
+		require.NoError(t, err)
+
+		last := toolpath.Moves[len(toolpath.Moves)-1]
+		assert.InDelta(t, 10, last.ToZ, 1e-9)
+
+		deepest := 0.0
+		for _, move := range toolpath.Moves {
+			deepest = min(deepest, move.ToZ)
+		}
+
+		assert.InDelta(t, -3, deepest, 1e-9)
+	})
+
+	t.Run("chip breaking", func(t *testing.T) {
+		toolpath, err := simulator.Simulate(strings.NewReader("G0 Z10\nG98\nG73 X5 Y5 Z-3 R2 Q2 F60\nG80\n"), 0, gcode.PostNone)
+		require.NoError(t, err)
+
+		heights := []float64{}
+		for _, move := range toolpath.Moves {
+			heights = append(heights, move.ToZ)
+		}
+
+		assert.InDeltaSlice(t, []float64{10, 10, 2, 0, 0.2, 0, -2, -1.8, -2, -3, 10}, heights, 1e-9)
+	})
+
+	t.Run("dwell in milliseconds", func(t *testing.T) {
+		toolpath, err := simulator.Simulate(strings.NewReader("G4 P1500\n"), 0, gcode.PostFanuc)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1500*time.Millisecond, toolpath.Duration)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := simulator.Simulate(strings.NewReader("G90\nG18\n"), 0, gcode.PostNone)
+		require.ErrorIs(t, err, simulator.ErrUnsupported)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestSimulateEngraving(t *testing.T) {
+	path := geometry.Path{
+		&geometry.Segment{StartPoint: geometry.Coordinates{X: 0, Y: 0}, EndPoint: geometry.Coordinates{X: 10, Y: 0}},
+		&geometry.Curve{
+			StartPoint: geometry.Coordinates{X: 10, Y: 0},
+			EndPoint:   geometry.Coordinates{X: 10, Y: 10},
+			Center:     geometry.Coordinates{X: 10, Y: 5},
+			Radius:     5,
+			Clockwise:  true,
+		},
+	}
+
+	code, err := gcode.Marshal(path, gcode.WithDeep(1), gcode.WithFeed(100), gcode.WithSecurityZ(5))
+	require.NoError(t, err)
+
+	for _, post := range []gcode.Post{gcode.PostNone, gcode.PostFanuc} {
+		var out bytes.Buffer
+
+		writer := gcode.NewWriter(&out, post, gcode.Spindle{})
+
+		_, err := writer.Write(append([]byte("G90\nG21\nG0 Z5\n"), code...))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		toolpath, err := simulator.New(0, 0, 5, 0, post).Run(&out)
+		require.NoError(t, err)
+
+		assert.InDelta(t, 6+10+5*3.14159265+6, toolpath.Distance, 1e-6, post.String())
+	}
+}
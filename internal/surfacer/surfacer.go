@@ -3,7 +3,6 @@ package surfacer
 import (
 	"fmt"
 	"io"
-	"time"
 
 	"github.com/landru29/cnc-drilling/internal/configuration"
 	"github.com/landru29/cnc-drilling/internal/geometry"
@@ -11,8 +10,8 @@ import (
 )
 
 // Process is the surfacing process.
-func Process(box geometry.Box, step float64, out io.Writer, info io.Writer, config configuration.Config, method Method) error {
-	if _, err := fmt.Fprintf(out, "G90\nG21\nG0 Z%.01f\n", config.SecurityZ); err != nil {
+func Process(box geometry.Box, step float64, out io.Writer, config configuration.Config, method Method) error {
+	if _, err := fmt.Fprint(out, config.Post.Preamble(config.SecurityZ)); err != nil {
 		return err
 	}
 
@@ -20,12 +19,11 @@ func Process(box geometry.Box, step float64, out io.Writer, info io.Writer, conf
 		return err
 	}
 
-	tryDeeps := config.TryDeeps()
+	if _, err := fmt.Fprint(out, config.Spindle().Start()); err != nil {
+		return err
+	}
 
-	var (
-		distance float64       = 0
-		duration time.Duration = 0
-	)
+	tryDeeps := config.TryDeeps()
 
 	for deepIndex, deep := range tryDeeps {
 		if _, err := fmt.Fprintf(
@@ -39,29 +37,29 @@ func Process(box geometry.Box, step float64, out io.Writer, info io.Writer, conf
 
 		switch method {
 		case MethodZigzag:
-			if err := surfaceAreaZigzag(box, step, out, config, deep, &distance, &duration); err != nil {
+			if err := surfaceAreaZigzag(box, step, out, config, deep); err != nil {
 				return err
 			}
 		case MethodSpiral:
-			if err := surfaceAreaSpiral(box, step, out, config, deep, true, &distance, &duration); err != nil {
+			if err := surfaceAreaSpiral(box, step, out, config, deep, true); err != nil {
 				return err
 			}
 		case MethodSpiralInverted:
-			if err := surfaceAreaSpiral(box, step, out, config, deep, false, &distance, &duration); err != nil {
+			if err := surfaceAreaSpiral(box, step, out, config, deep, false); err != nil {
 				return err
 			}
 		case MethodSpiralFromCenter:
-			if err := surfaceAreaSpiralFromCenter(box, step, out, config, deep, true, &distance, &duration); err != nil {
+			if err := surfaceAreaSpiralFromCenter(box, step, out, config, deep, true); err != nil {
 				return err
 			}
 		case MethodSpiralFromCenterInverted:
-			if err := surfaceAreaSpiralFromCenter(box, step, out, config, deep, false, &distance, &duration); err != nil {
+			if err := surfaceAreaSpiralFromCenter(box, step, out, config, deep, false); err != nil {
 				return err
 			}
 		}
 	}
 
-	if _, err := fmt.Fprintf(out, "G0 Z%.01f\n", config.SecurityZ); err != nil {
+	if _, err := fmt.Fprint(out, config.Spindle().Stop()); err != nil {
 		return err
 	}
 
@@ -69,10 +67,6 @@ func Process(box geometry.Box, step float64, out io.Writer, info io.Writer, conf
 		return err
 	}
 
-	if _, err := fmt.Fprintf(info, "; Total distance: %.01f mm\n; Total time: %s\n", distance, duration.Round(time.Second).String()); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -82,20 +76,15 @@ func surfaceAreaZigzag(
 	out io.Writer,
 	config configuration.Config,
 	deep float64,
-	distance *float64,
-	duration *time.Duration,
 ) error {
-	if _, err := fmt.Fprintf(out, "G0 Z%.01f\n", config.SecurityZ); err != nil {
-		return err
-	}
-
 	if _, err := fmt.Fprintf(out, "G0 X%.01f Y%.01f\n", box.Min.X, box.Min.Y); err != nil {
 		return err
 	}
 
 	path := machine.NewPath(box.Min.X, box.Min.Y, config.SecurityZ)
+	path.RapidFeed = config.RapidFeed
 
-	if err := path.MoveToZ(-deep, config.Feed, out); err != nil {
+	if err := path.Plunge(-deep, config.Clearance, config.PlungeRate(), out); err != nil {
 		return err
 	}
 
@@ -118,8 +107,9 @@ func surfaceAreaZigzag(
 		positiveX = !positiveX
 	}
 
-	*distance += path.Distance
-	*duration += path.Duration
+	if err := path.Retract(config.SecurityZ, config.RetractFeed, out); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -131,20 +121,15 @@ func surfaceAreaSpiral(
 	config configuration.Config,
 	deep float64,
 	clockwise bool,
-	distance *float64,
-	duration *time.Duration,
 ) error {
-	if _, err := fmt.Fprintf(out, "G0 Z%.01f\n", config.SecurityZ); err != nil {
-		return err
-	}
-
 	if _, err := fmt.Fprintf(out, "G0 X%.01f Y%.01f\n", box.Min.X, box.Min.Y); err != nil {
 		return err
 	}
 
 	path := machine.NewPath(box.Min.X, box.Min.Y, config.SecurityZ)
+	path.RapidFeed = config.RapidFeed
 
-	if err := path.MoveToZ(-deep, config.Feed, out); err != nil {
+	if err := path.Plunge(-deep, config.Clearance, config.PlungeRate(), out); err != nil {
 		return err
 	}
 
@@ -213,8 +198,9 @@ func surfaceAreaSpiral(
 		}
 	}
 
-	*distance += path.Distance
-	*duration += path.Duration
+	if err := path.Retract(config.SecurityZ, config.RetractFeed, out); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -226,13 +212,7 @@ func surfaceAreaSpiralFromCenter(
 	config configuration.Config,
 	deep float64,
 	clockwise bool,
-	distance *float64,
-	duration *time.Duration,
 ) error {
-	if _, err := fmt.Fprintf(out, "G0 Z%.01f\n", config.SecurityZ); err != nil {
-		return err
-	}
-
 	schema := geometry.Box{
 		Min: geometry.Coordinates{X: (box.Min.X + box.Max.X) / 2, Y: (box.Min.Y + box.Max.Y) / 2},
 		Max: geometry.Coordinates{X: (box.Min.X + box.Max.X) / 2, Y: (box.Min.Y + box.Max.Y) / 2},
@@ -256,7 +236,8 @@ func surfaceAreaSpiralFromCenter(
 	}
 
 	path := machine.NewPath(schema.Min.X, schema.Min.Y, config.SecurityZ)
-	if err := path.MoveToZ(-deep, config.Feed, out); err != nil {
+	path.RapidFeed = config.RapidFeed
+	if err := path.Plunge(-deep, config.Clearance, config.PlungeRate(), out); err != nil {
 		return err
 	}
 
@@ -288,8 +269,9 @@ func surfaceAreaSpiralFromCenter(
 		}
 	}
 
-	*distance += path.Distance
-	*duration += path.Duration
+	if err := path.Retract(config.SecurityZ, config.RetractFeed, out); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,63 @@
+G90
+G21
+G0 Z5.0
+
+;
+;=== Path #0 1/1 ===
+G0 X60.000 Y242.000
+G1 Z-1.000 F60.000; Tool down
+;------ Curve #40 / Layer 0
+G2 X68.000 Y250.000 I8.000 J0.000 F60.000
+;------ Segment #41 / Layer 0
+G1 X72.000 Y250.000 F60.000
+;------ Curve #42 / Layer 0
+G2 X80.000 Y242.000 I0.000 J-8.000 F60.000
+;------ Segment #43 / Layer 0
+G1 X80.000 Y178.000 F60.000
+;------ Curve #44 / Layer 0
+G2 X72.000 Y170.000 I-8.000 J0.000 F60.000
+;------ Segment #45 / Layer 0
+G1 X48.000 Y170.000 F60.000
+;------ Curve #46 / Layer 0
+G2 X40.000 Y178.000 I0.000 J8.000 F60.000
+;------ Segment #47 / Layer 0
+G1 X40.000 Y182.000 F60.000
+;------ Curve #48 / Layer 0
+G2 X48.000 Y190.000 I8.000 J0.000 F60.000
+;------ Segment #58 / Layer 0
+G1 X55.000 Y190.000 F60.000
+;------ Curve #60 / Layer 0
+G3 X60.000 Y195.000 I0.000 J5.000 F60.000
+;------ Segment #59 / Layer 0
+G1 X60.000 Y242.000 F60.000
+G0 Z5.000; Tool up
+;
+;=== Path #1 1/1 ===
+G0 X40.000 Y82.000
+G1 Z-1.000 F60.000; Tool down
+;------ Curve #55 / Layer 0
+G2 X48.000 Y90.000 I8.000 J0.000 F60.000
+;------ Segment #54 / Layer 0
+G1 X112.000 Y90.000 F60.000
+;------ Curve #49 / Layer 0
+G2 X120.000 Y82.000 I0.000 J-8.000 F60.000
+;------ Segment #50 / Layer 0
+G1 X120.000 Y58.000 F60.000
+;------ Curve #51 / Layer 0
+G2 X112.000 Y50.000 I-8.000 J0.000 F60.000
+;------ Segment #52 / Layer 0
+G1 X108.000 Y50.000 F60.000
+;------ Curve #53 / Layer 0
+G2 X100.000 Y58.000 I0.000 J8.000 F60.000
+;------ Segment #62 / Layer 0
+G1 X100.000 Y65.000 F60.000
+;------ Curve #63 / Layer 0
+G3 X95.000 Y70.000 I-5.000 J0.000 F60.000
+;------ Segment #61 / Layer 0
+G1 X48.000 Y70.000 F60.000
+;------ Curve #57 / Layer 0
+G2 X40.000 Y78.000 I0.000 J8.000 F60.000
+;------ Segment #56 / Layer 0
+G1 X40.000 Y82.000 F60.000
+G0 Z5.000; Tool up
+G0X0Y0